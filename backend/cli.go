@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/importer"
+	"report-management-system/internal/migrations"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const usage = `usage:
+  go run . migrate           apply pending schema migrations
+  go run . migrate status    list migrations and whether they are applied
+  go run . migrate refs      re-map legacy department names to department IDs
+  go run . import users <file.csv> [flags]     upsert users by email
+  go run . import reports <file.csv> [flags]   upsert reports by (user, date)
+      -map field=Column,...   CSV column for each field (default: column named like the field)
+      -date-format 02.01.2006 Go layout of the date column (default 2006-01-02)
+      -dry-run                validate only and print per-row errors
+      -skip-invalid           write valid rows even if some rows are invalid
+  go run . import list                         list import batches
+  go run . import rollback <batchId>           undo an import batch`
+
+func runCLI(ctx context.Context, args []string) error {
+	switch args[0] {
+	case "migrate":
+		return migrateCmd(ctx, args[1:])
+	case "import":
+		return importCmd(ctx, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+	}
+}
+
+func migrateCmd(ctx context.Context, args []string) error {
+	sub := "up"
+	if len(args) > 0 {
+		sub = args[0]
+	}
+	switch sub {
+	case "up":
+		if err := db.InitDepartments(ctx); err != nil {
+			return err
+		}
+		recs, err := migrations.Run(ctx)
+		if err != nil {
+			return err
+		}
+		if len(recs) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		return nil
+	case "status":
+		list, err := migrations.List(ctx)
+		if err != nil {
+			return err
+		}
+		for _, m := range list {
+			state := "pending"
+			if m.Applied {
+				state = "applied " + m.AppliedAt.Format("2006-01-02 15:04")
+			}
+			fmt.Printf("%4d  %-28s %s  %s\n", m.Version, m.Name, state, m.Notes)
+		}
+		return nil
+	case "refs":
+		rep, err := db.MigrateDepartmentRefs(ctx)
+		if err != nil {
+			return err
+		}
+		return printJSON(rep)
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", sub, usage)
+	}
+}
+
+func importCmd(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing import command\n%s", usage)
+	}
+	switch sub := args[0]; sub {
+	case "list":
+		items, err := importer.ListBatches(ctx, 100)
+		if err != nil {
+			return err
+		}
+		for _, b := range items {
+			fmt.Printf("%s  %-8s %-11s %s  rows=%d inserted=%d updated=%d skipped=%d  %s\n",
+				b.ID.Hex(), b.Kind, b.Status, b.CreatedAt.Format("2006-01-02 15:04"),
+				b.Rows, b.Inserted, b.Updated, b.Skipped, b.Source)
+		}
+		return nil
+	case "rollback":
+		if len(args) < 2 {
+			return fmt.Errorf("missing batch id\n%s", usage)
+		}
+		oid, err := primitive.ObjectIDFromHex(args[1])
+		if err != nil {
+			return fmt.Errorf("bad batch id %q", args[1])
+		}
+		res, err := importer.Rollback(ctx, oid, primitive.NilObjectID)
+		if err != nil {
+			return err
+		}
+		return printJSON(res)
+	case "users", "reports":
+		fs := flag.NewFlagSet("import "+sub, flag.ContinueOnError)
+		mapping := fs.String("map", "", "field=Column,...")
+		dateFormat := fs.String("date-format", "", "Go date layout")
+		dryRun := fs.Bool("dry-run", false, "validate only")
+		skipInvalid := fs.Bool("skip-invalid", false, "write valid rows even if some are invalid")
+
+		// bayraklar dosya adından önce veya sonra gelebilir
+		var pos []string
+		for rest := args[1:]; ; {
+			if err := fs.Parse(rest); err != nil {
+				return err
+			}
+			if fs.NArg() == 0 {
+				break
+			}
+			pos, rest = append(pos, fs.Arg(0)), fs.Args()[1:]
+		}
+		if len(pos) != 1 {
+			return fmt.Errorf("expected exactly one CSV file\n%s", usage)
+		}
+
+		opt := importer.Options{
+			Mapping:     map[string]string{},
+			DateFormat:  *dateFormat,
+			DryRun:      *dryRun,
+			SkipInvalid: *skipInvalid,
+			Source:      filepath.Base(pos[0]),
+		}
+		for _, pair := range strings.Split(*mapping, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			field, col, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("bad -map entry %q (want field=Column)", pair)
+			}
+			opt.Mapping[strings.TrimSpace(field)] = strings.TrimSpace(col)
+		}
+
+		f, err := os.Open(pos[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := db.EnsureImportIndexes(ctx); err != nil {
+			return err
+		}
+
+		run := importer.ImportUsers
+		if sub == "reports" {
+			run = importer.ImportReports
+		}
+		res, err := run(ctx, f, opt)
+		if res != nil {
+			if perr := printJSON(res); perr != nil {
+				return perr
+			}
+		}
+		return err
+	default:
+		return fmt.Errorf("unknown import command %q\n%s", sub, usage)
+	}
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
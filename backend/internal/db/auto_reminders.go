@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func EnsureAutoReminderIndexes(ctx context.Context) error {
+	col := Col("auto_reminder_schedules")
+	if col == nil {
+		return nil
+	}
+
+	// departman başına tek takvim; departmentId'siz kayıt şirket varsayılanı
+	if _, err := col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "departmentId", Value: 1}},
+		Options: options.Index().SetName("uniq_department").SetUnique(true),
+	}); err != nil {
+		return err
+	}
+
+	// tekrar çalıştırmada aynı kişiye ikinci hatırlatma/bildirim gitmesin
+	if _, err := Col("reminders").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "autoRunId", Value: 1}, {Key: "targetUserIds", Value: 1}},
+		Options: options.Index().SetName("uniq_auto_run_target").SetUnique(true).
+			SetPartialFilterExpression(bson.M{"autoRunId": bson.M{"$exists": true}}),
+	}); err != nil {
+		return err
+	}
+	if _, err := Col("notifications").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "sourceId", Value: 1}, {Key: "userId", Value: 1}},
+		Options: options.Index().SetName("uniq_missing_reports_source_user").SetUnique(true).
+			SetPartialFilterExpression(bson.M{"type": "missing_reports"}),
+	}); err != nil {
+		return err
+	}
+
+	_, err := Col("auto_reminder_runs").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// idempotency: (departman, gün, tür) başına tek çalıştırma
+			Keys: bson.D{
+				{Key: "departmentId", Value: 1},
+				{Key: "date", Value: 1},
+				{Key: "kind", Value: 1},
+			},
+			Options: options.Index().SetName("uniq_dept_date_kind").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "startedAt", Value: -1}},
+			Options: options.Index().SetName("started"),
+		},
+	})
+	return err
+}
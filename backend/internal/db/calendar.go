@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func EnsureCalendarIndexes(ctx context.Context) error {
+	col := Col("holidays")
+	if col == nil {
+		return nil
+	}
+
+	if _, err := col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		// aynı gün için şirket geneli (departmentId yok) ve departman başına tek kayıt
+		Keys: bson.D{
+			{Key: "date", Value: 1},
+			{Key: "departmentId", Value: 1},
+		},
+		Options: options.Index().SetName("uniq_date_dept").SetUnique(true),
+	}); err != nil {
+		return err
+	}
+
+	_, err := Col("leaves").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "from", Value: 1},
+			},
+			Options: options.Index().SetName("user_from"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "departmentId", Value: 1},
+				{Key: "from", Value: 1},
+			},
+			Options: options.Index().SetName("dept_from"),
+		},
+	})
+	return err
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func EnsureCommentIndexes(ctx context.Context) error {
+	col := Col("report_comments")
+	if col == nil {
+		return nil
+	}
+
+	if _, err := col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "reportId", Value: 1},
+				{Key: "createdAt", Value: 1},
+			},
+			Options: options.Index().SetName("report_created"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "reportOwnerId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("owner_created"),
+		},
+	}); err != nil {
+		return err
+	}
+
+	_, err := Col("comment_reads").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "userId", Value: 1},
+			{Key: "reportId", Value: 1},
+		},
+		Options: options.Index().SetName("uniq_user_report").SetUnique(true),
+	})
+	return err
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -81,9 +82,14 @@ func UpsertDepartments(ctx context.Context, names []string) (int, error) {
 	return int(res.UpsertedCount), nil
 }
 
-// SABİT (dosya içi) listeden seed et.
+// SABİT (dosya içi) listeden seed et. Sadece koleksiyon boşken: aksi halde
+// API'den yeniden adlandırılan/silinen departmanlar her açılışta geri gelirdi.
 func seedDepartmentsFromBuiltIn(ctx context.Context) error {
-	_, err := UpsertDepartments(ctx, BuiltInDepartments)
+	cnt, err := departmentsCol().CountDocuments(ctx, bson.M{})
+	if err != nil || cnt > 0 {
+		return err
+	}
+	_, err = UpsertDepartments(ctx, BuiltInDepartments)
 	return err
 }
 
@@ -132,7 +138,7 @@ func InitDepartments(ctx context.Context) error {
 	if err := ensureDepartmentIndexes(ctx); err != nil {
 		return err
 	}
-	// Dosya içindeki sabit listeden upsert
+	// Dosya içindeki sabit listeden upsert (koleksiyon boşsa)
 	if err := seedDepartmentsFromBuiltIn(ctx); err != nil {
 		return err
 	}
@@ -142,3 +148,92 @@ func InitDepartments(ctx context.Context) error {
 	}
 	return nil
 }
+
+// Eşleşmeyen (departments koleksiyonunda karşılığı olmayan) departman adı.
+type UnmatchedDepartment struct {
+	Collection string `json:"collection"`
+	Value      string `json:"value"`
+	Count      int64  `json:"count"`
+}
+
+type DepartmentRefReport struct {
+	UsersMapped     int64                 `json:"usersMapped"`
+	RemindersMapped int64                 `json:"remindersMapped"`
+	InvitesMapped   int64                 `json:"invitesMapped"`
+	Unmatched       []UnmatchedDepartment `json:"unmatched"`
+}
+
+// MigrateDepartmentRefs: eski serbest metin departman adlarını departments._id'ye
+// bağlar. Eşleşme trim + büyük/küçük harf duyarsızdır ("sales" -> "Sales"); adlar
+// kanonik hale getirilir. Sadece departmentId'si olmayan kayıtlara dokunur, yani
+// tekrar çalıştırmak güvenlidir. Eşleşmeyenler raporlanır, değiştirilmez.
+func MigrateDepartmentRefs(ctx context.Context) (DepartmentRefReport, error) {
+	rep := DepartmentRefReport{Unmatched: []UnmatchedDepartment{}}
+
+	cur, err := departmentsCol().Find(ctx, bson.M{})
+	if err != nil {
+		return rep, err
+	}
+	type depRef struct {
+		ID   primitive.ObjectID `bson:"_id"`
+		Name string             `bson:"name"`
+	}
+	var deps []depRef
+	if err := cur.All(ctx, &deps); err != nil {
+		return rep, err
+	}
+	byKey := make(map[string]depRef, len(deps))
+	for _, d := range deps {
+		byKey[strings.ToLower(strings.TrimSpace(d.Name))] = d
+	}
+
+	type target struct {
+		col, nameField, idField string
+		skip                    []string // eşlenmeyecek özel değerler
+		mapped                  *int64
+	}
+	targets := []target{
+		{"users", "department", "departmentId", nil, &rep.UsersMapped},
+		{"reminders", "targetDepartment", "targetDepartmentId", []string{"all"}, &rep.RemindersMapped},
+		{"invites", "department", "departmentId", nil, &rep.InvitesMapped},
+	}
+
+	for _, t := range targets {
+		col := Col(t.col)
+		pending := bson.M{
+			t.idField:   bson.M{"$exists": false},
+			t.nameField: bson.M{"$nin": append([]string{""}, t.skip...), "$exists": true},
+		}
+		vals, err := col.Distinct(ctx, t.nameField, pending)
+		if err != nil {
+			return rep, err
+		}
+		for _, v := range vals {
+			raw, ok := v.(string)
+			if !ok {
+				continue
+			}
+			filter := bson.M{t.idField: bson.M{"$exists": false}, t.nameField: raw}
+
+			d, found := byKey[strings.ToLower(strings.TrimSpace(raw))]
+			if !found {
+				n, err := col.CountDocuments(ctx, filter)
+				if err != nil {
+					return rep, err
+				}
+				rep.Unmatched = append(rep.Unmatched, UnmatchedDepartment{Collection: t.col, Value: raw, Count: n})
+				continue
+			}
+
+			res, err := col.UpdateMany(ctx, filter, bson.M{"$set": bson.M{
+				t.idField:   d.ID,
+				t.nameField: d.Name,
+			}})
+			if err != nil {
+				return rep, err
+			}
+			*t.mapped += res.ModifiedCount
+		}
+	}
+	return rep, nil
+}
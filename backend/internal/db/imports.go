@@ -0,0 +1,37 @@
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func EnsureImportIndexes(ctx context.Context) error {
+	col := Col("import_backups")
+	if col == nil {
+		return nil
+	}
+
+	if _, err := col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "batchId", Value: 1},
+			{Key: "docId", Value: 1},
+		},
+		Options: options.Index().SetName("uniq_batch_doc").SetUnique(true),
+	}); err != nil {
+		return err
+	}
+
+	// geri alma için batch'e ait kayıtları bulmak
+	for _, name := range []string{"reports", "users"} {
+		if _, err := Col(name).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "importBatchId", Value: 1}},
+			Options: options.Index().SetName("import_batch").SetSparse(true),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -23,6 +23,52 @@ func EnsureReminderIndexes(ctx context.Context) error {
 				SetName("active_dept_exp_created").
 				SetPartialFilterExpression(bson.M{"isActive": true}),
 		},
+		{
+			Keys: bson.D{
+				{Key: "isActive", Value: 1},
+				{Key: "targetDepartmentId", Value: 1},
+				{Key: "expiresAt", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().
+				SetName("active_deptid_exp_created").
+				SetPartialFilterExpression(bson.M{"isActive": true}),
+		},
+		// hedef kitle listeleri (multikey); ListMyReminders her hedef türü için
+		// {isActive, <hedef>, expiresAt} + createdAt sıralı ayrı bir $or kolu kullanır
+		{
+			Keys: bson.D{
+				{Key: "isActive", Value: 1},
+				{Key: "targetDepartmentIds", Value: 1},
+				{Key: "expiresAt", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().
+				SetName("active_deptids_exp_created").
+				SetPartialFilterExpression(bson.M{"isActive": true}),
+		},
+		{
+			Keys: bson.D{
+				{Key: "isActive", Value: 1},
+				{Key: "targetRoles", Value: 1},
+				{Key: "expiresAt", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().
+				SetName("active_roles_exp_created").
+				SetPartialFilterExpression(bson.M{"isActive": true}),
+		},
+		{
+			Keys: bson.D{
+				{Key: "isActive", Value: 1},
+				{Key: "targetUserIds", Value: 1},
+				{Key: "expiresAt", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().
+				SetName("active_users_exp_created").
+				SetPartialFilterExpression(bson.M{"isActive": true}),
+		},
 		{
 			Keys: bson.D{
 				{Key: "senderId", Value: 1},
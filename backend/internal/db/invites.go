@@ -0,0 +1,31 @@
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func EnsureInviteIndexes(ctx context.Context) error {
+	col := Col("invites")
+	if col == nil {
+		return nil
+	}
+
+	_, err := col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tokenHash", Value: 1}},
+			Options: options.Index().SetName("uniq_tokenHash").SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "departmentId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("dept_created"),
+		},
+	})
+	return err
+}
@@ -208,6 +208,10 @@ func EnsureIndexes(ctx context.Context) error {
 			Keys:    bson.D{{Key: "department", Value: 1}},
 			Options: options.Index().SetName("idx_department"),
 		},
+		{
+			Keys:    bson.D{{Key: "departmentId", Value: 1}},
+			Options: options.Index().SetName("idx_departmentId"),
+		},
 	}); err != nil {
 		return err
 	}
@@ -227,6 +231,11 @@ func EnsureIndexes(ctx context.Context) error {
 			Keys:    bson.D{{Key: "content", Value: "text"}},
 			Options: options.Index().SetName("txt_content"),
 		},
+		{
+			// onay kuyruğu
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "date", Value: 1}},
+			Options: options.Index().SetName("status_date"),
+		},
 	}); err != nil {
 		return err
 	}
@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func EnsureProjectIndexes(ctx context.Context) error {
+	col := Col("projects")
+	if col == nil {
+		return nil
+	}
+
+	if _, err := col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "code", Value: 1}},
+			Options: options.Index().SetName("uniq_code").SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "departmentId", Value: 1},
+				{Key: "active", Value: 1},
+				{Key: "name", Value: 1},
+			},
+			Options: options.Index().SetName("dept_active_name"),
+		},
+	}); err != nil {
+		return err
+	}
+
+	// proje saat analitiği: iş kalemi -> proje
+	_, err := Col("reports").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "entries.projectId", Value: 1},
+			{Key: "date", Value: 1},
+		},
+		Options: options.Index().
+			SetName("entries_project_date").
+			SetPartialFilterExpression(bson.M{"entries.projectId": bson.M{"$exists": true}}),
+	})
+	return err
+}
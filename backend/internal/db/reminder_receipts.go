@@ -0,0 +1,25 @@
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func EnsureReminderReceiptIndexes(ctx context.Context) error {
+	_, err := Col("reminder_receipts").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// hatırlatma başına kullanıcı başına tek durum kaydı (gönderen istatistikleri)
+			Keys:    bson.D{{Key: "reminderId", Value: 1}, {Key: "userId", Value: 1}},
+			Options: options.Index().SetName("uniq_reminder_user").SetUnique(true),
+		},
+		{
+			// ListMyReminders: kullanıcının listelenen hatırlatmalardaki durumu
+			Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "reminderId", Value: 1}},
+			Options: options.Index().SetName("user_reminder"),
+		},
+	})
+	return err
+}
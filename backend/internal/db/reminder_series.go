@@ -0,0 +1,35 @@
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func EnsureReminderSeriesIndexes(ctx context.Context) error {
+	if _, err := Col("reminder_series").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// zamanlayıcı: sıradaki tekrar
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "nextRunAt", Value: 1}},
+			Options: options.Index().SetName("status_next"),
+		},
+		{
+			Keys:    bson.D{{Key: "template.senderId", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("sender_created"),
+		},
+	}); err != nil {
+		return err
+	}
+
+	// idempotency: seri başına her tekrar tek hatırlatma
+	_, err := Col("reminders").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "seriesId", Value: 1}, {Key: "occurrenceAt", Value: 1}},
+		Options: options.Index().
+			SetName("uniq_series_occurrence").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"seriesId": bson.M{"$exists": true}}),
+	})
+	return err
+}
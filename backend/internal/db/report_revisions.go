@@ -0,0 +1,27 @@
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func EnsureReportRevisionIndexes(ctx context.Context) error {
+	col := Col("report_revisions")
+	if col == nil {
+		return nil
+	}
+
+	_, err := col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "reportId", Value: 1},
+				{Key: "rev", Value: 1},
+			},
+			Options: options.Index().SetName("uniq_report_rev").SetUnique(true),
+		},
+	})
+	return err
+}
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func EnsureReportUnlockIndexes(ctx context.Context) error {
+	col := Col("report_unlocks")
+	if col == nil {
+		return nil
+	}
+
+	_, err := col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "date", Value: 1},
+				{Key: "status", Value: 1},
+			},
+			Options: options.Index().SetName("user_date_status"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "departmentId", Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("dept_status_created"),
+		},
+	})
+	return err
+}
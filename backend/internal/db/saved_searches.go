@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func EnsureSavedSearchIndexes(ctx context.Context) error {
+	col := Col("saved_searches")
+	if col == nil {
+		return nil
+	}
+
+	if _, err := col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "ownerId", Value: 1},
+				{Key: "name", Value: 1},
+			},
+			Options: options.Index().SetName("uniq_owner_name").SetUnique(true),
+		},
+		{
+			// zamanlayıcı sadece zamanlanmış kayıtları tarar
+			Keys:    bson.D{{Key: "nextRunAt", Value: 1}},
+			Options: options.Index().SetName("next_run").SetSparse(true),
+		},
+	}); err != nil {
+		return err
+	}
+
+	_, err := Col("notifications").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "userId", Value: 1},
+			{Key: "createdAt", Value: -1},
+		},
+		Options: options.Index().SetName("user_created"),
+	})
+	return err
+}
@@ -0,0 +1,39 @@
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func EnsureSessionIndexes(ctx context.Context) error {
+	col := Col("sessions")
+	if col == nil {
+		return nil
+	}
+
+	_, err := col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "refreshHash", Value: 1}},
+			Options: options.Index().SetName("uniq_refreshHash").SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "prevRefreshHash", Value: 1}},
+			Options: options.Index().
+				SetName("prevRefreshHash").
+				SetPartialFilterExpression(bson.M{"prevRefreshHash": bson.M{"$exists": true}}),
+		},
+		{
+			Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "revokedAt", Value: 1}},
+			Options: options.Index().SetName("user_revoked"),
+		},
+		{
+			// süresi dolan oturumlar Mongo tarafından silinir
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("ttl_expiresAt").SetExpireAfterSeconds(0),
+		},
+	})
+	return err
+}
@@ -0,0 +1,46 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+type csvWriter struct {
+	w    *csv.Writer
+	rows int
+}
+
+func newCSV(w io.Writer) *csvWriter {
+	// Excel'in UTF-8 olarak açması için BOM
+	_, _ = io.WriteString(w, "\ufeff")
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) Header(cols []Column) error {
+	titles := make([]string, len(cols))
+	for i, col := range cols {
+		titles[i] = col.Title
+	}
+	return c.w.Write(titles)
+}
+
+func (c *csvWriter) Row(vals []any) error {
+	rec := make([]string, len(vals))
+	for i, v := range vals {
+		rec[i] = sheetString(v)
+	}
+	if err := c.w.Write(rec); err != nil {
+		return err
+	}
+	// ara ara boşalt ki istemci akışı görsün
+	if c.rows++; c.rows%500 == 0 {
+		c.w.Flush()
+		return c.w.Error()
+	}
+	return nil
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
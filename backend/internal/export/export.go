@@ -0,0 +1,88 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Satır satır yazan tablo dışa aktarıcı. Satırlar geldikçe çıktıya akıtılır;
+// sonuç belleğe toplanmaz (sadece XLSX/PDF'in küçük sabit parçaları Close'da yazılır).
+type Writer interface {
+	Header(cols []Column) error
+	Row(vals []any) error // string, float64, int
+	Close() error
+}
+
+type Column struct {
+	Title string
+	Width float64 // PDF'te sütun genişliği (pt), XLSX'te karakter genişliği ~ Width/5
+}
+
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+	PDF  Format = "pdf"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case CSV, XLSX, PDF:
+		return f, nil
+	case "":
+		return CSV, nil
+	default:
+		return "", fmt.Errorf("unknown export format %q", s)
+	}
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case XLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case PDF:
+		return "application/pdf"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+func (f Format) Ext() string { return "." + string(f) }
+
+// New: title XLSX'te sayfa adı, PDF'te başlık olarak kullanılır.
+func New(f Format, w io.Writer, title string) Writer {
+	switch f {
+	case XLSX:
+		return newXLSX(w, title)
+	case PDF:
+		return newPDF(w, title)
+	default:
+		return newCSV(w)
+	}
+}
+
+// Tablo programlarında (Excel, LibreOffice) formül olarak yorumlanabilecek
+// metinlerin (=, +, -, @, sekme, CR ile başlayan) başına ' eklenir: rapor içeriği
+// ve adlar çalışanlardan gelir (CSV injection). Sayılar olduğu gibi kalır.
+func sheetString(v any) string {
+	s := cellString(v)
+	if _, ok := v.(string); ok && s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func cellString(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case float64:
+		return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", x), "0"), ".")
+	default:
+		return fmt.Sprint(x)
+	}
+}
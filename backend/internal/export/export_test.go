@@ -0,0 +1,28 @@
+package export
+
+import "testing"
+
+func TestSheetString(t *testing.T) {
+	tests := []struct {
+		in   any
+		want string
+	}{
+		{"=HYPERLINK(\"http://x\")", "'=HYPERLINK(\"http://x\")"},
+		{"+1+1", "'+1+1"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+		{"wrote tests", "wrote tests"},
+		{"a=b", "a=b"},
+		{"", ""},
+		{nil, ""},
+		{-1.5, "-1.5"},
+		{-3, "-3"},
+	}
+	for _, tt := range tests {
+		if got := sheetString(tt.in); got != tt.want {
+			t.Errorf("sheetString(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
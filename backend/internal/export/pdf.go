@@ -0,0 +1,276 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Harici kütüphanesiz, sayfalı PDF tablo (A4 yatay, Helvetica). Her sayfa dolunca
+// hemen yazılır; bellekte yalnızca o anki sayfa ve nesne ofsetleri tutulur.
+//
+// Standart Helvetica WinAnsi kodlamasıyla sınırlıdır: ş/ğ/ı gibi karakterler
+// en yakın ASCII karşılığına çevrilir.
+type pdfWriter struct {
+	w     *countingWriter
+	title string
+
+	cols    []Column
+	offsets map[int]int64 // nesne no -> dosya ofseti
+	nextObj int
+	pages   []int // sayfa nesne numaraları
+
+	page   bytes.Buffer
+	y      float64
+	pageNo int
+	err    error
+}
+
+const (
+	pdfPageW     = 842.0
+	pdfPageH     = 595.0
+	pdfMargin    = 36.0
+	pdfFontSize  = 9.0
+	pdfLeading   = 11.0
+	pdfCellPad   = 4.0
+	pdfMaxLines  = 8   // hücre başına en fazla satır; fazlası "..." ile kesilir
+	pdfCharWidth = 0.5 // Helvetica ortalama karakter genişliği (font boyutuna oranla)
+
+	pdfObjCatalog = 1
+	pdfObjPages   = 2
+	pdfObjFont    = 3
+	pdfObjBold    = 4
+)
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func newPDF(w io.Writer, title string) *pdfWriter {
+	p := &pdfWriter{w: &countingWriter{w: w}, title: title, offsets: map[int]int64{}, nextObj: pdfObjBold + 1}
+	p.raw("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	p.object(pdfObjCatalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pdfObjPages))
+	p.object(pdfObjFont, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	p.object(pdfObjBold, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	return p
+}
+
+func (p *pdfWriter) raw(s string) {
+	if p.err == nil {
+		_, p.err = io.WriteString(p.w, s)
+	}
+}
+
+func (p *pdfWriter) object(num int, body string) {
+	p.offsets[num] = p.w.n
+	p.raw(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", num, body))
+}
+
+func (p *pdfWriter) alloc() int {
+	n := p.nextObj
+	p.nextObj++
+	return n
+}
+
+func (p *pdfWriter) Header(cols []Column) error {
+	p.cols = cols
+	p.startPage()
+	return p.err
+}
+
+func (p *pdfWriter) startPage() {
+	p.page.Reset()
+	p.pageNo++
+	p.y = pdfPageH - pdfMargin
+
+	p.text(pdfMargin, p.y-14, 14, true, p.title)
+	p.text(pdfPageW-pdfMargin-160, p.y-12, 8, false, "Generated "+time.Now().Format("2006-01-02 15:04"))
+	p.y -= 28
+
+	if len(p.cols) > 0 {
+		titles := make([]any, len(p.cols))
+		for i, c := range p.cols {
+			titles[i] = c.Title
+		}
+		p.drawRow(titles, true)
+	}
+}
+
+func (p *pdfWriter) finishPage() {
+	p.text(pdfPageW/2-15, pdfMargin/2, 8, false, fmt.Sprintf("Page %d", p.pageNo))
+
+	content := p.page.Bytes()
+	contentObj, pageObj := p.alloc(), p.alloc()
+	p.offsets[contentObj] = p.w.n
+	p.raw(fmt.Sprintf("%d 0 obj\n<< /Length %d >>\nstream\n", contentObj, len(content)))
+	if p.err == nil {
+		_, p.err = p.w.Write(content)
+	}
+	p.raw("\nendstream\nendobj\n")
+	p.object(pageObj, fmt.Sprintf(
+		"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.0f %.0f] /Contents %d 0 R /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> >>",
+		pdfObjPages, pdfPageW, pdfPageH, contentObj, pdfObjFont, pdfObjBold))
+	p.pages = append(p.pages, pageObj)
+}
+
+func (p *pdfWriter) Row(vals []any) error {
+	if p.err != nil {
+		return p.err
+	}
+	if p.pageNo == 0 {
+		p.startPage()
+	}
+	p.drawRow(vals, false)
+	return p.err
+}
+
+func (p *pdfWriter) drawRow(vals []any, bold bool) {
+	cells := make([][]string, len(vals))
+	lines := 1
+	for i, v := range vals {
+		w := 100.0
+		if i < len(p.cols) {
+			w = p.cols[i].Width
+		}
+		cells[i] = wrapText(cellString(v), w-2*pdfCellPad)
+		if len(cells[i]) > lines {
+			lines = len(cells[i])
+		}
+	}
+	h := float64(lines)*pdfLeading + 2*pdfCellPad
+
+	// sığmıyorsa yeni sayfa (başlık satırı kendisi tekrar çizer)
+	if !bold && p.y-h < pdfMargin {
+		p.finishPage()
+		p.startPage()
+	}
+
+	x := pdfMargin
+	for i, cell := range cells {
+		for j, line := range cell {
+			p.text(x+pdfCellPad, p.y-pdfCellPad-pdfFontSize-float64(j)*pdfLeading, pdfFontSize, bold, line)
+		}
+		if i < len(p.cols) {
+			x += p.cols[i].Width
+		}
+	}
+	p.y -= h
+	width := 0.5
+	if bold {
+		width = 1
+	}
+	fmt.Fprintf(&p.page, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, pdfMargin, p.y, pdfPageW-pdfMargin, p.y)
+}
+
+func (p *pdfWriter) text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfString(s))
+}
+
+func (p *pdfWriter) Close() error {
+	if p.pageNo == 0 {
+		p.startPage()
+	}
+	p.finishPage()
+
+	kids := make([]string, len(p.pages))
+	for i, n := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", n)
+	}
+	p.object(pdfObjPages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+
+	xref := p.w.n
+	var b strings.Builder
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", p.nextObj)
+	for i := 1; i < p.nextObj; i++ {
+		fmt.Fprintf(&b, "%010d 00000 n \n", p.offsets[i])
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", p.nextObj, pdfObjCatalog, xref)
+	p.raw(b.String())
+	return p.err
+}
+
+// Metni yaklaşık genişliğe göre satırlara böler (kelime sınırından; çok uzun kelimeler kesilir).
+func wrapText(s string, width float64) []string {
+	maxChars := int(width / (pdfFontSize * pdfCharWidth))
+	if maxChars < 1 {
+		maxChars = 1
+	}
+	var out []string
+	for _, para := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			for utf8.RuneCountInString(word) > maxChars {
+				if line != "" {
+					out = append(out, line)
+					line = ""
+				}
+				r := []rune(word)
+				out = append(out, string(r[:maxChars]))
+				word = string(r[maxChars:])
+			}
+			switch {
+			case line == "":
+				line = word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= maxChars:
+				line += " " + word
+			default:
+				out = append(out, line)
+				line = word
+			}
+		}
+		out = append(out, line)
+	}
+	// sondaki boş satırları at
+	for len(out) > 1 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	if len(out) > pdfMaxLines {
+		out = append(out[:pdfMaxLines-1], out[pdfMaxLines-1]+" ...")
+	}
+	return out
+}
+
+// WinAnsi'de olmayan Türkçe harfler için ASCII karşılıkları.
+var pdfTransliterate = map[rune]string{
+	'ş': "s", 'Ş': "S", 'ğ': "g", 'Ğ': "G", 'ı': "i", 'İ': "I",
+	'…': "...", '’': "'", '‘': "'", '“': "\"", '”': "\"", '–': "-", '—': "-",
+}
+
+// PDF literal string: WinAnsi (Latin-1 aralığı) + ( ) \ kaçışları.
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if t, ok := pdfTransliterate[r]; ok {
+			b.WriteString(t)
+			continue
+		}
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r == '\t':
+			b.WriteByte(' ')
+		case r >= 0x20 && r < 0x7f:
+			b.WriteByte(byte(r))
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
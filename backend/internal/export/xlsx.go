@@ -0,0 +1,179 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Tek sayfalık, harici kütüphanesiz XLSX (Office Open XML). Sabit parçalar
+// baştan yazılır; sheet1.xml zip içinde akış olarak yazılır, hücreler inline string.
+type xlsxWriter struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	row   int
+	err   error
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+	// s="1": kalın başlık, s="2": metin sarma
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0" applyAlignment="1"><alignment vertical="top" wrapText="1"/></xf></cellXfs>
+</styleSheet>`
+)
+
+func newXLSX(w io.Writer, title string) *xlsxWriter {
+	x := &xlsxWriter{zw: zip.NewWriter(w)}
+	x.put("[Content_Types].xml", xlsxContentTypes)
+	x.put("_rels/.rels", xlsxRootRels)
+	x.put("xl/_rels/workbook.xml.rels", xlsxWorkbookRels)
+	x.put("xl/styles.xml", xlsxStyles)
+	x.put("xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="`+xmlEscape(sheetName(title))+`" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`)
+	if x.err == nil {
+		var f io.Writer
+		f, x.err = x.zw.Create("xl/worksheets/sheet1.xml")
+		x.sheet = bufio.NewWriter(f)
+	}
+	return x
+}
+
+func (x *xlsxWriter) put(name, body string) {
+	if x.err != nil {
+		return
+	}
+	f, err := x.zw.Create(name)
+	if err == nil {
+		_, err = io.WriteString(f, body)
+	}
+	x.err = err
+}
+
+// Sayfa adı: en fazla 31 karakter, []:*?/\ yasak.
+func sheetName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(s))
+	if s == "" {
+		s = "Sheet1"
+	}
+	if r := []rune(s); len(r) > 31 {
+		s = string(r[:31])
+	}
+	return s
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// 0 -> A, 25 -> Z, 26 -> AA
+func colName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+func (x *xlsxWriter) Header(cols []Column) error {
+	if x.err != nil {
+		return x.err
+	}
+	fmt.Fprint(x.sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+"\n"+
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`+
+		`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews><cols>`)
+	for i, c := range cols {
+		w := c.Width / 5
+		if w < 8 {
+			w = 8
+		}
+		fmt.Fprintf(x.sheet, `<col min="%d" max="%d" width="%.1f" customWidth="1"/>`, i+1, i+1, w)
+	}
+	fmt.Fprint(x.sheet, `</cols><sheetData>`)
+
+	vals := make([]any, len(cols))
+	for i, c := range cols {
+		vals[i] = c.Title
+	}
+	return x.writeRow(vals, 1)
+}
+
+func (x *xlsxWriter) Row(vals []any) error {
+	return x.writeRow(vals, 0)
+}
+
+func (x *xlsxWriter) writeRow(vals []any, style int) error {
+	if x.err != nil {
+		return x.err
+	}
+	x.row++
+	fmt.Fprintf(x.sheet, `<row r="%d">`, x.row)
+	for i, v := range vals {
+		ref := colName(i) + strconv.Itoa(x.row)
+		switch n := v.(type) {
+		case float64:
+			fmt.Fprintf(x.sheet, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(n, 'f', -1, 64))
+		case int:
+			fmt.Fprintf(x.sheet, `<c r="%s"><v>%d</v></c>`, ref, n)
+		default:
+			s := sheetString(v)
+			st := style
+			if st == 0 && strings.Contains(s, "\n") {
+				st = 2
+			}
+			fmt.Fprintf(x.sheet, `<c r="%s" t="inlineStr" s="%d"><is><t xml:space="preserve">%s</t></is></c>`, ref, st, xmlEscape(s))
+		}
+	}
+	_, err := fmt.Fprint(x.sheet, `</row>`)
+	return err
+}
+
+func (x *xlsxWriter) Close() error {
+	if x.err != nil {
+		return x.err
+	}
+	if x.row == 0 {
+		// Header çağrılmadıysa da geçerli bir sayfa yaz
+		fmt.Fprint(x.sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+"\n"+
+			`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	}
+	fmt.Fprint(x.sheet, `</sheetData></worksheet>`)
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zw.Close()
+}
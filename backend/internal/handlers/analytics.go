@@ -2,28 +2,34 @@ package handlers
 
 import (
 	"net/http"
-	"sort"
-	"strings"
 	"time"
 
 	"report-management-system/internal/db"
+	"report-management-system/internal/models"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type companyStats struct {
 	TotalEmployees int64   `json:"totalEmployees"`
 	ReportsToday   int64   `json:"reportsToday"`
 	Departments    int64   `json:"departments"`
-	AvgHours       float64 `json:"avgHours"`
+	AvgHours       float64 `json:"avgHours"`      // çalışma günlerindeki raporlar
+	WorkingDay     bool    `json:"workingDay"`    // bugün (şirket varsayılan takvimi)
+	ExpectedToday  int64   `json:"expectedToday"` // bugün rapor beklenen kişi (izinliler hariç)
+	OnLeaveToday   int64   `json:"onLeaveToday"`
 }
 
 type deptOverview struct {
-	Department   string  `json:"department"`
-	Employees    int64   `json:"employees"`
-	ReportsToday int64   `json:"reportsToday"`
-	AvgHours     float64 `json:"avgHours"` // seçilen periyottaki ortalama saat
+	Department    string  `json:"department"`
+	Employees     int64   `json:"employees"`
+	ReportsToday  int64   `json:"reportsToday"`
+	AvgHours      float64 `json:"avgHours"` // seçilen periyottaki ortalama saat (çalışma günleri)
+	ExpectedToday int64   `json:"expectedToday"`
+	OnLeaveToday  int64   `json:"onLeaveToday"`
 }
 
 type compareSeries struct {
@@ -56,54 +62,13 @@ func CompanyAnalytics(c *gin.Context) {
 	}
 
 	// ----- resmi departman listesi -----
-	// Önce departments koleksiyonundan oku; yoksa kullanıcıların distinct( department )'ina düs.
-	deps := make([]string, 0, 16)
-
-	if depCol := db.Col("departments"); depCol != nil {
-		cur, _ := depCol.Find(ctx, bson.M{})
-		var docs []struct {
-			Name       string `bson:"name"`
-			Department string `bson:"department"`
-			Title      string `bson:"title"`
-		}
-		_ = cur.All(ctx, &docs)
-		for _, d := range docs {
-			name := strings.TrimSpace(d.Name)
-			if name == "" {
-				name = strings.TrimSpace(d.Department)
-			}
-			if name == "" {
-				name = strings.TrimSpace(d.Title)
-			}
-			if name != "" {
-				deps = append(deps, name)
-			}
-		}
-	}
-	if len(deps) == 0 {
-		rawDeps, _ := users.Distinct(ctx, "department", bson.M{"department": bson.M{"$ne": ""}})
-		for _, v := range rawDeps {
-			if s, ok := v.(string); ok && strings.TrimSpace(s) != "" {
-				deps = append(deps, strings.TrimSpace(s))
-			}
-		}
-	}
-	// normalize + uniq + sort
-	if len(deps) > 0 {
-		m := map[string]struct{}{}
-		out := make([]string, 0, len(deps))
-		for _, d := range deps {
-			d = strings.TrimSpace(d)
-			if d == "" {
-				continue
-			}
-			if _, seen := m[d]; !seen {
-				m[d] = struct{}{}
-				out = append(out, d)
-			}
-		}
-		sort.Strings(out)
-		deps = out
+	// departments koleksiyonundan (arşivlenmemiş) oku; kullanıcılar departmentId ile eşlenir.
+	var deps []models.Department
+	if cur, err := db.Col("departments").Find(ctx,
+		bson.M{"active": true},
+		options.Find().SetSort(bson.D{{Key: "name", Value: 1}}),
+	); err == nil {
+		_ = cur.All(ctx, &deps)
 	}
 	deptCount := int64(len(deps))
 
@@ -118,12 +83,47 @@ func CompanyAnalytics(c *gin.Context) {
 	}
 
 	// ----- company stats -----
-	totalEmployees, _ := users.CountDocuments(ctx, bson.M{})
+	totalEmployees, _ := users.CountDocuments(ctx, activeUserFilter())
 	today := time.Now().Format("2006-01-02")
-	reportsToday, _ := reports.CountDocuments(ctx, bson.M{"date": today})
+	// taslaklar henüz raporlanmamış sayılır (durum ekranı ve uyum ile aynı); saat toplamlarına da girmez
+	notDraft := bson.M{"$ne": models.ReportDraft}
+	reportsToday, _ := reports.CountDocuments(ctx, bson.M{"date": today, "status": notDraft})
+
+	// ----- çalışma takvimi: hafta sonu, tatil ve izin günleri ortalamaya girmez -----
+	wc, err := loadWorkCalendar(ctx, fromStr, today, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	var activeUsers []models.User
+	if cur, err := users.Find(ctx, activeUserFilter(),
+		options.Find().SetProjection(bson.M{"_id": 1, "departmentId": 1}),
+	); err == nil {
+		_ = cur.All(ctx, &activeUsers)
+	}
+	expectedToday := map[primitive.ObjectID]int64{}
+	onLeaveToday := map[primitive.ObjectID]int64{}
+	var expectedAll, onLeaveAll int64
+	for _, u := range activeUsers {
+		if wc.leaveOn(u.ID, today) != nil {
+			onLeaveToday[u.DepartmentID]++
+			onLeaveAll++
+		} else if wc.isWorkingDay(u.DepartmentID, today) {
+			expectedToday[u.DepartmentID]++
+			expectedAll++
+		}
+	}
 
 	curAvgCompany, _ := reports.Aggregate(ctx, []bson.M{
-		{"$match": bson.M{"date": bson.M{"$gte": fromStr}}},
+		{"$match": bson.M{"date": bson.M{"$gte": fromStr}, "status": notDraft}},
+		{"$lookup": bson.M{
+			"from":         "users",
+			"localField":   "userId",
+			"foreignField": "_id",
+			"as":           "u",
+		}},
+		{"$unwind": bson.M{"path": "$u", "preserveNullAndEmptyArrays": true}},
+		{"$match": wc.workingReportsMatch(nil, fromStr, today)},
 		{"$group": bson.M{"_id": nil, "avg": bson.M{"$avg": "$hours"}}},
 	})
 	var avgRows []struct {
@@ -139,16 +139,21 @@ func CompanyAnalytics(c *gin.Context) {
 		ReportsToday:   reportsToday,
 		Departments:    deptCount,
 		AvgHours:       avgHours,
+		WorkingDay:     wc.isWorkingDay(primitive.NilObjectID, today),
+		ExpectedToday:  expectedAll,
+		OnLeaveToday:   onLeaveAll,
 	}
 
 	// ----- Department Overview -----
 	overview := make([]deptOverview, 0, len(deps))
 	for _, d := range deps {
-		empCount, _ := users.CountDocuments(ctx, bson.M{"department": d})
+		empCount, _ := users.CountDocuments(ctx, bson.M{
+			"$and": []bson.M{{"departmentId": d.ID}, activeUserFilter()},
+		})
 
 		// Bugünün rapor sayısı (departman bazında)
 		pToday := []bson.M{
-			{"$match": bson.M{"date": today}},
+			{"$match": bson.M{"date": today, "status": notDraft}},
 			{"$lookup": bson.M{
 				"from":         "users",
 				"localField":   "userId",
@@ -156,7 +161,7 @@ func CompanyAnalytics(c *gin.Context) {
 				"as":           "u",
 			}},
 			{"$unwind": "$u"},
-			{"$match": bson.M{"u.department": d}},
+			{"$match": bson.M{"u.departmentId": d.ID}},
 			{"$count": "n"},
 		}
 		curToday, _ := reports.Aggregate(ctx, pToday)
@@ -171,7 +176,7 @@ func CompanyAnalytics(c *gin.Context) {
 
 		// Seçilen periyotta departman ortalama saat
 		pAvgDept := []bson.M{
-			{"$match": bson.M{"date": bson.M{"$gte": fromStr}}},
+			{"$match": bson.M{"date": bson.M{"$gte": fromStr}, "status": notDraft}},
 			{"$lookup": bson.M{
 				"from":         "users",
 				"localField":   "userId",
@@ -179,7 +184,8 @@ func CompanyAnalytics(c *gin.Context) {
 				"as":           "u",
 			}},
 			{"$unwind": "$u"},
-			{"$match": bson.M{"u.department": d}},
+			{"$match": bson.M{"u.departmentId": d.ID}},
+			{"$match": wc.workingReportsMatch(&d.ID, fromStr, today)},
 			{"$group": bson.M{"_id": nil, "avg": bson.M{"$avg": "$hours"}}},
 		}
 		curAvgDept, _ := reports.Aggregate(ctx, pAvgDept)
@@ -193,10 +199,12 @@ func CompanyAnalytics(c *gin.Context) {
 		}
 
 		overview = append(overview, deptOverview{
-			Department:   d,
-			Employees:    empCount,
-			ReportsToday: rpt,
-			AvgHours:     avgDept,
+			Department:    d.Name,
+			Employees:     empCount,
+			ReportsToday:  rpt,
+			AvgHours:      avgDept,
+			ExpectedToday: expectedToday[d.ID],
+			OnLeaveToday:  onLeaveToday[d.ID],
 		})
 	}
 
@@ -225,7 +233,7 @@ func CompanyAnalytics(c *gin.Context) {
 		points := make([]float64, len(labelKeys))
 
 		p := []bson.M{
-			{"$match": bson.M{"date": bson.M{"$gte": fromStr}}},
+			{"$match": bson.M{"date": bson.M{"$gte": fromStr}, "status": notDraft}},
 			{"$lookup": bson.M{
 				"from":         "users",
 				"localField":   "userId",
@@ -233,7 +241,7 @@ func CompanyAnalytics(c *gin.Context) {
 				"as":           "u",
 			}},
 			{"$unwind": "$u"},
-			{"$match": bson.M{"u.department": d}},
+			{"$match": bson.M{"u.departmentId": d.ID}},
 		}
 		if mode == "days" {
 			p = append(p, bson.M{"$group": bson.M{
@@ -267,7 +275,7 @@ func CompanyAnalytics(c *gin.Context) {
 			Department string    `json:"department"`
 			Points     []float64 `json:"points"`
 		}{
-			Department: d,
+			Department: d.Name,
 			Points:     points,
 		})
 	}
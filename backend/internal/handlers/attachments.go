@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/models"
+	"report-management-system/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultAttachmentMaxBytes = 10 << 20 // 10 MB
+	maxAttachmentsPerReport   = 10
+)
+
+var defaultAttachmentTypes = []string{
+	"image/png", "image/jpeg", "image/gif", "image/webp",
+	"application/pdf",
+	"text/plain", // loglar
+}
+
+// ATTACHMENT_MAX_BYTES: tek dosya üst sınırı (bayt)
+func attachmentMaxBytes() int64 {
+	if v := strings.TrimSpace(os.Getenv("ATTACHMENT_MAX_BYTES")); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultAttachmentMaxBytes
+}
+
+// ATTACHMENT_ALLOWED_TYPES: virgülle ayrılmış MIME listesi (içerikten tespit edilen tip kontrol edilir)
+func attachmentTypeAllowed(ct string) bool {
+	allowed := defaultAttachmentTypes
+	if v := strings.TrimSpace(os.Getenv("ATTACHMENT_ALLOWED_TYPES")); v != "" {
+		allowed = strings.Split(v, ",")
+	}
+	for _, a := range allowed {
+		if strings.EqualFold(strings.TrimSpace(a), ct) {
+			return true
+		}
+	}
+	return false
+}
+
+// Dosya adından yol ve kontrol karakterlerini temizler.
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		name = "file"
+	}
+	if len(name) > 200 {
+		name = name[len(name)-200:]
+	}
+	return name
+}
+
+// Ek ekleme/silme yetkisi; izin yoksa yanıtı kendisi yazar.
+// - rapor sahibi: içerikle aynı kurallar (onaylanmamış ve tarih kilitli değil / unlock var)
+// - raporu yönetebilen admin/superadmin
+func canEditAttachments(c *gin.Context, rep models.Report) bool {
+	if rep.UserID.Hex() == c.GetString("userId") {
+		if rep.Status == models.ReportApproved {
+			c.JSON(http.StatusForbidden, gin.H{"error": errReportApproved.Error(), "code": "REPORT_APPROVED"})
+			return false
+		}
+		me, err := currentUser(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+			return false
+		}
+		return requireReportUnlocked(c, me, rep.Date)
+	}
+	allowed := false
+	if c.GetString("role") != string(models.RoleEmployee) {
+		var owner models.User
+		if err := db.Col("users").FindOne(c.Request.Context(), bson.M{"_id": rep.UserID}).Decode(&owner); err != nil {
+			allowed = c.GetString("role") == string(models.RoleSuperAdmin)
+		} else {
+			allowed = canModifyUser(c, owner)
+		}
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	}
+	return allowed
+}
+
+// POST /api/reports/:id/attachments  (multipart, alan adı: file)
+func UploadAttachment(c *gin.Context) {
+	ctx := c.Request.Context()
+	store := storage.Current()
+	if store == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "storage not configured"})
+		return
+	}
+	maxBytes := attachmentMaxBytes()
+	// multipart zarfı için biraz pay bırak
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes+1<<20)
+
+	rep, ok := loadAccessibleReport(c)
+	if !ok {
+		return
+	}
+	if !canEditAttachments(c, rep) {
+		return
+	}
+	if len(rep.Attachments) >= maxAttachmentsPerReport {
+		c.JSON(http.StatusConflict, gin.H{"error": "attachment limit reached", "max": maxAttachmentsPerReport})
+		return
+	}
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file required (or request too large)"})
+		return
+	}
+	if fh.Size > maxBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file too large", "maxBytes": maxBytes})
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer f.Close()
+
+	// istemcinin gönderdiği Content-Type'a güvenme: içerikten tespit et
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(f, head)
+	head = head[:n]
+	ct, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if !attachmentTypeAllowed(ct) {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "file type not allowed", "type": ct})
+		return
+	}
+
+	name := sanitizeFileName(fh.Filename)
+	att := models.Attachment{
+		ID:          primitive.NewObjectID(),
+		FileName:    name,
+		ContentType: ct,
+		Size:        fh.Size,
+		UploadedBy:  toOID(c.GetString("userId")),
+		UploadedAt:  time.Now(),
+	}
+	att.Key = "reports/" + rep.ID.Hex() + "/" + att.ID.Hex() + strings.ToLower(filepath.Ext(name))
+
+	if err := store.Put(ctx, att.Key, io.MultiReader(bytes.NewReader(head), f), fh.Size, ct); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// limit şartı eşzamanlı yüklemelere karşı filtrede de var
+	res, err := db.Col("reports").UpdateOne(ctx,
+		bson.M{"_id": rep.ID, "attachments." + strconv.Itoa(maxAttachmentsPerReport-1): bson.M{"$exists": false}},
+		// ek eklenen rapor artık CSV import geri alımına dahil değil
+		bson.M{"$push": bson.M{"attachments": att}, "$unset": bson.M{"importBatchId": ""}},
+	)
+	if err != nil || res.MatchedCount == 0 {
+		_ = store.Delete(ctx, att.Key)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": "attachment limit reached", "max": maxAttachmentsPerReport})
+		return
+	}
+	c.JSON(http.StatusCreated, att)
+}
+
+func findAttachment(c *gin.Context, rep models.Report) (models.Attachment, bool) {
+	for _, a := range rep.Attachments {
+		if a.ID.Hex() == c.Param("attachmentId") {
+			return a, true
+		}
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": "attachment not found"})
+	return models.Attachment{}, false
+}
+
+// GET /api/reports/:id/attachments/:attachmentId
+// Erişim: rapor sahibi, departman admini, superadmin (GetUserReports ile aynı kurallar).
+func DownloadAttachment(c *gin.Context) {
+	store := storage.Current()
+	if store == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "storage not configured"})
+		return
+	}
+	rep, ok := loadAccessibleReport(c)
+	if !ok {
+		return
+	}
+	att, ok := findAttachment(c, rep)
+	if !ok {
+		return
+	}
+
+	rc, err := store.Get(c.Request.Context(), att.Key)
+	if err == storage.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "file missing"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer rc.Close()
+
+	c.DataFromReader(http.StatusOK, att.Size, att.ContentType, rc, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": att.FileName}),
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, no-store",
+	})
+}
+
+// DELETE /api/reports/:id/attachments/:attachmentId
+func DeleteAttachment(c *gin.Context) {
+	ctx := c.Request.Context()
+	rep, ok := loadAccessibleReport(c)
+	if !ok {
+		return
+	}
+	if !canEditAttachments(c, rep) {
+		return
+	}
+	att, ok := findAttachment(c, rep)
+	if !ok {
+		return
+	}
+
+	if _, err := db.Col("reports").UpdateByID(ctx, rep.ID,
+		bson.M{"$pull": bson.M{"attachments": bson.M{"_id": att.ID}}, "$unset": bson.M{"importBatchId": ""}},
+	); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if store := storage.Current(); store != nil {
+		_ = store.Delete(ctx, att.Key)
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
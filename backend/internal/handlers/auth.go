@@ -157,7 +157,6 @@ package handlers
 
 import (
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
@@ -165,7 +164,6 @@ import (
 	"report-management-system/internal/models"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -185,32 +183,34 @@ func Me(c *gin.Context) {
 	var u models.User
 	if err := db.Col("users").FindOne(c.Request.Context(), bson.M{"_id": oid}).Decode(&u); err != nil {
 		// c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
-		 c.JSON(http.StatusUnauthorized, gin.H{
-            "error": "Unauthorized",
-            "code":  "USER_NOT_FOUND",
-        })
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized",
+			"code":  "USER_NOT_FOUND",
+		})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"id":         u.ID.Hex(),
-		"name":       u.Name,
-		"email":      u.Email,
-		"role":       u.Role,
-		"department": u.Department,
-		"createdAt":  u.CreatedAt, 
+		"id":           u.ID.Hex(),
+		"name":         u.Name,
+		"email":        u.Email,
+		"role":         u.Role,
+		"departmentId": u.DepartmentID,
+		"department":   u.Department,
+		"createdAt":    u.CreatedAt,
 	})
 }
 
 // POST /api/auth/register
+// Rol ve departman davetten gelir; davetsiz kayıt sadece ALLOW_OPEN_REGISTRATION
+// açıksa ve her zaman employee olarak yapılır.
 func Register(c *gin.Context) {
 	var body struct {
-		Name       string      `json:"name"`
-		Email      string      `json:"email"`
-		Password   string      `json:"password"`
-		Role       string      `json:"role"`                  // optional, default: employee
-		Department string      `json:"department"`            // optional
-		CreatedAt  *time.Time  `json:"createdAt,omitempty"`   // optional (RFC3339)
+		Name        string `json:"name"`
+		Email       string `json:"email"`
+		Password    string `json:"password"`
+		InviteToken string `json:"inviteToken"` // zorunlu (open registration kapalıysa)
+		Department  string `json:"department"`  // sadece open registration için (ad veya ID)
 	}
 	if err := c.ShouldBindJSON(&body); err != nil ||
 		strings.TrimSpace(body.Name) == "" ||
@@ -220,8 +220,13 @@ func Register(c *gin.Context) {
 		return
 	}
 
+	token := strings.TrimSpace(body.InviteToken)
+	if token == "" && !openRegistrationEnabled() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "invite required"})
+		return
+	}
+
 	email := strings.ToLower(strings.TrimSpace(body.Email))
-	dept := strings.TrimSpace(body.Department)
 
 	// email var mı?
 	if err := db.Col("users").
@@ -241,39 +246,65 @@ func Register(c *gin.Context) {
 		return
 	}
 
-	// rol seçimi (default employee)
-	role := models.RoleEmployee
-	switch strings.ToLower(strings.TrimSpace(body.Role)) {
-	case "admin":
-		role = models.RoleAdmin
-	case "superadmin":
-		role = models.RoleSuperAdmin
-	}
-
-	// createdAt: geldiyse kullan, yoksa şimdi
-	created := time.Now().UTC()
-	if body.CreatedAt != nil && !body.CreatedAt.IsZero() {
-		created = body.CreatedAt.UTC()
-	}
-
 	u := models.User{
+		ID:           primitive.NewObjectID(),
 		Name:         strings.TrimSpace(body.Name),
 		Email:        email,
 		PasswordHash: string(hash),
-		Role:         role,
-		Department:   dept,
-		CreatedAt:    created, 
+		Role:         models.RoleEmployee,
+		CreatedAt:    time.Now().UTC(),
 	}
 
-	res, err := db.Col("users").InsertOne(c.Request.Context(), u)
-	if err != nil {
+	// davet varsa önce atomik olarak claim et, rol/departmanı oradan al
+	var inv models.Invite
+	if token != "" {
+		inv, err = claimInvite(c, token, u.ID, email)
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusForbidden, gin.H{"error": "invalid or expired invite"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		u.Role = inv.Role
+		if !inv.DepartmentID.IsZero() {
+			// davetten bu yana departman arşivlenmiş olabilir
+			d, err := resolveDepartment(c.Request.Context(), inv.DepartmentID.Hex(), true)
+			if err != nil {
+				releaseInvite(c, inv.ID)
+				departmentError(c, err)
+				return
+			}
+			u.DepartmentID, u.Department = d.ID, d.Name
+		}
+	} else {
+		d, err := resolveDepartment(c.Request.Context(), body.Department, true)
+		if err != nil {
+			departmentError(c, err)
+			return
+		}
+		u.DepartmentID, u.Department = d.ID, d.Name
+	}
+
+	if _, err := db.Col("users").InsertOne(c.Request.Context(), u); err != nil {
+		if token != "" {
+			releaseInvite(c, inv.ID)
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "email already exists"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"id":        res.InsertedID,
-		"createdAt": u.CreatedAt, 
+		"id":           u.ID,
+		"role":         u.Role,
+		"departmentId": u.DepartmentID,
+		"department":   u.Department,
+		"createdAt":    u.CreatedAt,
 	})
 }
 
@@ -304,24 +335,24 @@ func Login(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"}) // 401
 		return
 	}
-
-	claims := jwt.MapClaims{
-		"id":   u.ID.Hex(),
-		"role": string(u.Role),
-		"exp":  time.Now().Add(24 * time.Hour).Unix(),
+	if !u.Active() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "account deactivated", "code": "USER_DEACTIVATED"})
+		return
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	str, _ := token.SignedString([]byte(os.Getenv("JWT_SECRET")))
 
-	c.JSON(http.StatusOK, gin.H{
-		"token": str,
-		"user": gin.H{
-			"id":         u.ID.Hex(),
-			"name":       u.Name,
-			"role":       u.Role,
-			"email":      u.Email,
-			"department": u.Department,
-			"createdAt":  u.CreatedAt, 
-		},
-	})
+	tokens, err := issueSession(c, u)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	tokens["user"] = gin.H{
+		"id":           u.ID.Hex(),
+		"name":         u.Name,
+		"role":         u.Role,
+		"email":        u.Email,
+		"departmentId": u.DepartmentID,
+		"department":   u.Department,
+		"createdAt":    u.CreatedAt,
+	}
+	c.JSON(http.StatusOK, tokens)
 }
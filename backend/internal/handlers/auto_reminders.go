@@ -0,0 +1,531 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/mailer"
+	"report-management-system/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultAutoReminderPoll = time.Minute
+	autoReminderRunLease    = 15 * time.Minute // çalışma çökerse bu süre sonra tekrar alınır
+	autoReminderRetryDelay  = 5 * time.Minute  // hata veren çalıştırmanın yeniden denenmesi
+	defaultAutoReminderText = "You haven't submitted your daily report for %s yet. Please submit it today."
+	autoReminderSender      = "Report reminders"
+)
+
+// "HH:MM" -> o günün yerel saati
+func clockOn(day time.Time, hhmm string) (time.Time, bool) {
+	t, err := time.Parse("15:04", strings.TrimSpace(hhmm))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, day.Location()), true
+}
+
+// ---------- zamanlayıcı ----------
+
+// Departmanın o gün rapor beklenip de (taslak hariç) raporu olmayan aktif kullanıcıları.
+func missingReporters(ctx context.Context, wc *workCalendar, deptID primitive.ObjectID, date string) ([]models.User, error) {
+	cur, err := db.Col("users").Find(ctx, bson.M{
+		"$and": []bson.M{{"departmentId": deptID}, activeUserFilter()},
+		"role": bson.M{"$ne": models.RoleSuperAdmin},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var users []models.User
+	if err := cur.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	done, err := db.Col("reports").Distinct(ctx, "userId", bson.M{
+		"userId": bson.M{"$in": ids},
+		"date":   date,
+		"status": bson.M{"$ne": models.ReportDraft},
+	})
+	if err != nil {
+		return nil, err
+	}
+	reported := make(map[primitive.ObjectID]bool, len(done))
+	for _, v := range done {
+		if id, ok := v.(primitive.ObjectID); ok {
+			reported[id] = true
+		}
+	}
+	var out []models.User
+	for _, u := range users {
+		if !reported[u.ID] && wc.expectedFrom(u, date) {
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
+
+// Çalıştırmayı sahiplenir. (departman, gün, tür) için ilk kez çalışıyorsa kayıt
+// açılır; kayıt varsa sadece tamamlanmamış ve kirası bitmiş (çökmüş ya da hata
+// verip bekleme süresi geçmiş) çalıştırma yeniden alınır. Alınamazsa nil.
+func claimAutoReminderRun(ctx context.Context, d models.Department, date string, kind models.AutoReminderKind, now time.Time) (*models.AutoReminderRun, error) {
+	lease := now.Add(autoReminderRunLease)
+	run := &models.AutoReminderRun{
+		ID:           primitive.NewObjectID(),
+		DepartmentID: d.ID,
+		Department:   d.Name,
+		Date:         date,
+		Kind:         kind,
+		Status:       models.AutoRunRunning,
+		Attempts:     1,
+		LeaseUntil:   &lease,
+		StartedAt:    now,
+	}
+	_, err := db.Col("auto_reminder_runs").InsertOne(ctx, run)
+	if err == nil {
+		return run, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, err
+	}
+
+	run = &models.AutoReminderRun{}
+	err = db.Col("auto_reminder_runs").FindOneAndUpdate(ctx,
+		bson.M{
+			"departmentId": d.ID,
+			"date":         date,
+			"kind":         kind,
+			"status":       bson.M{"$ne": models.AutoRunCompleted},
+			"$or": bson.A{
+				bson.M{"leaseUntil": bson.M{"$exists": false}},
+				bson.M{"leaseUntil": bson.M{"$lte": now}},
+			},
+		},
+		bson.M{
+			"$set":   bson.M{"status": models.AutoRunRunning, "leaseUntil": lease, "department": d.Name},
+			"$inc":   bson.M{"attempts": 1},
+			"$unset": bson.M{"error": "", "finishedAt": ""},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(run)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil // tamamlandı ya da başka bir örnek çalıştırıyor
+	}
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func finishAutoReminderRun(ctx context.Context, run *models.AutoReminderRun, runErr error) {
+	now := time.Now()
+	lease := run.LeaseUntil
+	run.FinishedAt = &now
+	run.Status = models.AutoRunCompleted
+	run.Error = ""
+	run.LeaseUntil = nil
+	if runErr != nil {
+		retry := now.Add(autoReminderRetryDelay)
+		run.Status = models.AutoRunFailed
+		run.Error = runErr.Error()
+		run.LeaseUntil = &retry
+	}
+	// kira bu arada bittiyse çalıştırma başka bir örneğe geçmiş olabilir: dokunma
+	res, err := db.Col("auto_reminder_runs").ReplaceOne(ctx, bson.M{"_id": run.ID, "leaseUntil": lease}, run)
+	if err == nil && res.MatchedCount == 0 {
+		err = errors.New("lease lost")
+	}
+	if err != nil {
+		log.Printf("auto reminder run %s: %v", run.ID.Hex(), err)
+	}
+}
+
+// Cutoff: raporu olmayan her kullanıcıya kişisel hatırlatma (+ opsiyonel e-posta).
+func sendMissingReportReminders(ctx context.Context, s models.AutoReminderSchedule, run *models.AutoReminderRun, missing []models.User, now time.Time) error {
+	text := strings.TrimSpace(s.Message)
+	if text == "" {
+		text = fmt.Sprintf(defaultAutoReminderText, run.Date)
+	}
+	expires := now.Add(24 * time.Hour)
+	for _, u := range missing {
+		rem := models.Reminder{
+			Content:       text,
+			Type:          models.ReminderWarning,
+			TargetUserIDs: []primitive.ObjectID{u.ID},
+			AutoRunID:     &run.ID,
+			SenderName:    autoReminderSender,
+			Duration:      "temporary",
+			IsActive:      true,
+			ExpiresAt:     &expires,
+			CreatedAt:     now,
+		}
+		if _, err := db.Col("reminders").InsertOne(ctx, rem); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				continue // önceki denemede hatırlatıldı (e-postası da o denemeye ait)
+			}
+			return err
+		}
+		run.Reminders++
+
+		if s.Email && u.Email != "" {
+			if err := mailer.Send([]string{u.Email}, "[Reports] Daily report missing for "+run.Date, text); err != nil {
+				run.EmailErrors++
+			} else {
+				run.Emailed++
+			}
+		}
+	}
+	return nil
+}
+
+// Eskalasyon: hâlâ eksik olanların listesi departman admin'lerine bildirim (+ opsiyonel e-posta).
+func escalateMissingReports(ctx context.Context, s models.AutoReminderSchedule, run *models.AutoReminderRun, missing []models.User, now time.Time) error {
+	if len(missing) == 0 {
+		return nil
+	}
+	cur, err := db.Col("users").Find(ctx, bson.M{
+		"$and": []bson.M{{"departmentId": run.DepartmentID}, activeUserFilter()},
+		"role": models.RoleAdmin,
+	})
+	if err != nil {
+		return err
+	}
+	var admins []models.User
+	if err := cur.All(ctx, &admins); err != nil {
+		return err
+	}
+
+	title := fmt.Sprintf("%d employee(s) in %s have not reported for %s", len(missing), run.Department, run.Date)
+	var b strings.Builder
+	fmt.Fprintf(&b, "No report for %s after the %s reminder:\n\n", run.Date, s.Cutoff)
+	for _, u := range missing {
+		fmt.Fprintf(&b, "- %s <%s>\n", u.Name, u.Email)
+	}
+	body := b.String()
+
+	for _, a := range admins {
+		if _, err := notify(ctx, models.Notification{
+			UserID:    a.ID,
+			Type:      models.NotificationMissingReports,
+			Title:     title,
+			Body:      body,
+			SourceID:  &run.ID,
+			CreatedAt: now,
+		}); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				continue // önceki denemede bildirildi
+			}
+			return err
+		}
+		run.Notified++
+		if s.EscalateEmail && a.Email != "" {
+			if err := mailer.Send([]string{a.Email}, "[Reports] "+title, body); err != nil {
+				run.EmailErrors++
+			} else {
+				run.Emailed++
+			}
+		}
+	}
+	return nil
+}
+
+// Zamanı gelen departmanlar için hatırlatma/eskalasyonu bir kez çalıştırır. Sunucu
+// cutoff sırasında kapalıysa aynı gün içinde ilk turda telafi edilir.
+func runDueAutoReminders(ctx context.Context, now time.Time) {
+	var schedules []models.AutoReminderSchedule
+	cur, err := db.Col("auto_reminder_schedules").Find(ctx, bson.M{})
+	if err == nil {
+		err = cur.All(ctx, &schedules)
+	}
+	if err != nil {
+		log.Println("auto reminders:", err)
+		return
+	}
+	var def *models.AutoReminderSchedule
+	byDept := map[primitive.ObjectID]models.AutoReminderSchedule{}
+	for i, s := range schedules {
+		if s.DepartmentID == nil {
+			def = &schedules[i]
+		} else {
+			byDept[*s.DepartmentID] = s
+		}
+	}
+	if def == nil && len(byDept) == 0 {
+		return
+	}
+
+	var deps []models.Department
+	if cur, err := db.Col("departments").Find(ctx, bson.M{"active": true}); err == nil {
+		_ = cur.All(ctx, &deps)
+	}
+	date := now.Format("2006-01-02")
+	var wc *workCalendar
+
+	for _, d := range deps {
+		s, ok := byDept[d.ID]
+		if !ok {
+			if def == nil {
+				continue
+			}
+			s = *def
+		}
+		if !s.Enabled {
+			continue
+		}
+		cutoff, ok := clockOn(now, s.Cutoff)
+		if !ok || now.Before(cutoff) {
+			continue
+		}
+		if wc == nil {
+			if wc, err = loadWorkCalendar(ctx, date, date, nil); err != nil {
+				log.Println("auto reminders:", err)
+				return
+			}
+		}
+		if !wc.isWorkingDay(d.ID, date) {
+			continue
+		}
+
+		steps := []models.AutoReminderKind{models.AutoReminderNotice}
+		if esc, ok := clockOn(now, s.EscalateAt); ok && esc.After(cutoff) && !now.Before(esc) {
+			steps = append(steps, models.AutoReminderEscalation)
+		}
+		for _, kind := range steps {
+			run, err := claimAutoReminderRun(ctx, d, date, kind, now)
+			if err != nil {
+				log.Printf("auto reminders %s: %v", d.Name, err)
+				continue
+			}
+			if run == nil {
+				continue // bugün zaten çalıştı ya da başka bir örnekte sürüyor
+			}
+			missing, err := missingReporters(ctx, wc, d.ID, date)
+			if err == nil {
+				run.MissingUsers = nil // yeniden denemede güncel liste
+				for _, u := range missing {
+					run.MissingUsers = append(run.MissingUsers, u.ID)
+				}
+				if kind == models.AutoReminderNotice {
+					err = sendMissingReportReminders(ctx, s, run, missing, now)
+				} else {
+					err = escalateMissingReports(ctx, s, run, missing, now)
+				}
+			}
+			if err != nil {
+				log.Printf("auto reminders %s (%s): %v", d.Name, kind, err)
+			}
+			finishAutoReminderRun(ctx, run, err)
+		}
+	}
+}
+
+// StartAutoReminders: otomatik hatırlatmaları AUTO_REMINDER_POLL aralığıyla
+// (varsayılan 1m) kontrol eder; ctx iptal edilince durur.
+func StartAutoReminders(ctx context.Context) {
+	t := time.NewTicker(envDuration("AUTO_REMINDER_POLL", defaultAutoReminderPoll))
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-t.C:
+			runDueAutoReminders(ctx, now)
+		}
+	}
+}
+
+// ---------- ayarlar ----------
+
+// :department parametresi: "default" = şirket varsayılanı (sadece superadmin),
+// aksi halde departman adı/ID'si. Admin sadece kendi departmanı.
+func autoReminderDepartment(c *gin.Context) (*models.Department, bool) {
+	ref := strings.TrimSpace(c.Param("department"))
+	isAdmin := c.GetString("role") == string(models.RoleAdmin)
+	if strings.EqualFold(ref, "default") {
+		if isAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return nil, false
+		}
+		return nil, true
+	}
+	d, err := resolveDepartment(c.Request.Context(), ref, false)
+	if err != nil {
+		departmentError(c, err)
+		return nil, false
+	}
+	if isAdmin && d.ID != myDepartmentID(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return nil, false
+	}
+	return &d, true
+}
+
+func autoReminderScheduleFilter(d *models.Department) bson.M {
+	if d == nil {
+		return bson.M{"departmentId": bson.M{"$exists": false}}
+	}
+	return bson.M{"departmentId": d.ID}
+}
+
+// GET /api/reminders/auto  (admin: varsayılan + kendi departmanı, superadmin: hepsi)
+func ListAutoReminderSchedules(c *gin.Context) {
+	ctx := c.Request.Context()
+	filter := bson.M{}
+	if c.GetString("role") == string(models.RoleAdmin) {
+		filter = bson.M{"$or": []bson.M{
+			{"departmentId": bson.M{"$exists": false}},
+			{"departmentId": myDepartmentID(c)},
+		}}
+	}
+	cur, err := db.Col("auto_reminder_schedules").Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "department", Value: 1}}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	items := []models.AutoReminderSchedule{}
+	if err := cur.All(ctx, &items); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// PUT /api/reminders/auto/:department  {enabled, cutoff, escalateAt?, email, escalateEmail, message?}
+// (admin kendi departmanı; superadmin her departman ve "default")
+func PutAutoReminderSchedule(c *gin.Context) {
+	ctx := c.Request.Context()
+	d, ok := autoReminderDepartment(c)
+	if !ok {
+		return
+	}
+	var body struct {
+		Enabled       bool   `json:"enabled"`
+		Cutoff        string `json:"cutoff"`
+		EscalateAt    string `json:"escalateAt"`
+		Email         bool   `json:"email"`
+		EscalateEmail bool   `json:"escalateEmail"`
+		Message       string `json:"message"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return
+	}
+	today := time.Now()
+	cutoff, ok := clockOn(today, body.Cutoff)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cutoff must be HH:MM"})
+		return
+	}
+	if strings.TrimSpace(body.EscalateAt) != "" {
+		esc, ok := clockOn(today, body.EscalateAt)
+		if !ok || !esc.After(cutoff) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "escalateAt must be HH:MM and later than cutoff"})
+			return
+		}
+	}
+	if len(body.Message) > 1000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "message too long"})
+		return
+	}
+
+	s := models.AutoReminderSchedule{
+		Enabled:       body.Enabled,
+		Cutoff:        cutoff.Format("15:04"),
+		Email:         body.Email,
+		EscalateEmail: body.EscalateEmail,
+		Message:       strings.TrimSpace(body.Message),
+		UpdatedBy:     toOID(c.GetString("userId")),
+		UpdatedAt:     time.Now().UTC(),
+	}
+	if strings.TrimSpace(body.EscalateAt) != "" {
+		esc, _ := clockOn(today, body.EscalateAt)
+		s.EscalateAt = esc.Format("15:04")
+	}
+	if d != nil {
+		s.DepartmentID, s.Department = &d.ID, d.Name
+	}
+
+	var saved models.AutoReminderSchedule
+	err := db.Col("auto_reminder_schedules").FindOneAndReplace(ctx, autoReminderScheduleFilter(d), s,
+		options.FindOneAndReplace().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&saved)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, saved)
+}
+
+// DELETE /api/reminders/auto/:department — departman takvimini siler (varsayılana döner)
+func DeleteAutoReminderSchedule(c *gin.Context) {
+	d, ok := autoReminderDepartment(c)
+	if !ok {
+		return
+	}
+	res, err := db.Col("auto_reminder_schedules").DeleteOne(c.Request.Context(), autoReminderScheduleFilter(d))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if res.DeletedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// GET /api/reminders/auto/runs?department=&date=&limit=50  (admin kendi departmanı, superadmin hepsi)
+func ListAutoReminderRuns(c *gin.Context) {
+	ctx := c.Request.Context()
+	filter := bson.M{}
+	if c.GetString("role") == string(models.RoleAdmin) {
+		filter["departmentId"] = myDepartmentID(c)
+	} else if dep := strings.TrimSpace(c.Query("department")); dep != "" {
+		d, err := resolveDepartment(ctx, dep, false)
+		if err != nil {
+			departmentError(c, err)
+			return
+		}
+		filter["departmentId"] = d.ID
+	}
+	if date := strings.TrimSpace(c.Query("date")); date != "" {
+		filter["date"] = date
+	}
+	limit := int64(50)
+	if v := c.Query("limit"); v != "" {
+		if n, e := strconv.ParseInt(v, 10, 64); e == nil && n > 0 && n <= 200 {
+			limit = n
+		}
+	}
+	cur, err := db.Col("auto_reminder_runs").Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "startedAt", Value: -1}}).SetLimit(limit))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	items := []models.AutoReminderRun{}
+	if err := cur.All(ctx, &items); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
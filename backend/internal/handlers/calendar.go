@@ -0,0 +1,648 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const maxCalendarDays = 366
+
+// Durum ekranında kullanıcının o günkü durumu
+const (
+	dayStateSubmitted  = "submitted"
+	dayStateDraft      = "draft"
+	dayStateMissing    = "missing"
+	dayStateOnLeave    = "on_leave"
+	dayStateNonWorking = "non_working_day"
+)
+
+// Bir tarih aralığı için tatiller, departman çalışma günleri ve kullanıcı izinleri.
+// Uyum (compliance) ve ortalama saat hesapları çalışma dışı günleri bununla ayıklar.
+type workCalendar struct {
+	holidays map[string]map[primitive.ObjectID]string // tarih -> departman (NilObjectID = şirket) -> ad
+	workdays map[primitive.ObjectID][7]bool
+	leaves   map[primitive.ObjectID][]models.Leave // userId -> izinler
+}
+
+func weekdaySet(days []int) [7]bool {
+	var set [7]bool
+	for _, d := range days {
+		if d >= 0 && d < 7 {
+			set[d] = true
+		}
+	}
+	return set
+}
+
+// from..to (dahil) aralığındaki tatilleri, tüm departmanların çalışma günlerini ve
+// verilen kullanıcıların izinlerini yükler. userIDs nil ise aralıktaki tüm izinler.
+func loadWorkCalendar(ctx context.Context, from, to string, userIDs []primitive.ObjectID) (*workCalendar, error) {
+	wc := &workCalendar{
+		holidays: map[string]map[primitive.ObjectID]string{},
+		workdays: map[primitive.ObjectID][7]bool{},
+		leaves:   map[primitive.ObjectID][]models.Leave{},
+	}
+
+	var hs []models.Holiday
+	cur, err := db.Col("holidays").Find(ctx, bson.M{"date": bson.M{"$gte": from, "$lte": to}})
+	if err != nil {
+		return nil, err
+	}
+	if err := cur.All(ctx, &hs); err != nil {
+		return nil, err
+	}
+	for _, h := range hs {
+		dep := primitive.NilObjectID
+		if h.DepartmentID != nil {
+			dep = *h.DepartmentID
+		}
+		if wc.holidays[h.Date] == nil {
+			wc.holidays[h.Date] = map[primitive.ObjectID]string{}
+		}
+		wc.holidays[h.Date][dep] = h.Name
+	}
+
+	var deps []models.Department
+	cur, err = db.Col("departments").Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"_id": 1, "workDays": 1}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cur.All(ctx, &deps); err != nil {
+		return nil, err
+	}
+	for _, d := range deps {
+		wc.workdays[d.ID] = weekdaySet(d.Workweek())
+	}
+
+	lf := bson.M{"from": bson.M{"$lte": to}, "to": bson.M{"$gte": from}}
+	if userIDs != nil {
+		if len(userIDs) == 0 {
+			return wc, nil
+		}
+		lf["userId"] = bson.M{"$in": userIDs}
+	}
+	var ls []models.Leave
+	cur, err = db.Col("leaves").Find(ctx, lf)
+	if err != nil {
+		return nil, err
+	}
+	if err := cur.All(ctx, &ls); err != nil {
+		return nil, err
+	}
+	for _, l := range ls {
+		wc.leaves[l.UserID] = append(wc.leaves[l.UserID], l)
+	}
+	return wc, nil
+}
+
+// Şirket geneli ya da departmana özel tatil adı.
+func (wc *workCalendar) holiday(deptID primitive.ObjectID, date string) (string, bool) {
+	hs := wc.holidays[date]
+	if name, ok := hs[primitive.NilObjectID]; ok {
+		return name, true
+	}
+	if deptID.IsZero() {
+		return "", false
+	}
+	name, ok := hs[deptID]
+	return name, ok
+}
+
+// Departmanın çalışma günü mü (haftalık takvim + tatiller)? Departmansız kullanıcılar
+// varsayılan (Pazartesi-Cuma) takvimi kullanır.
+func (wc *workCalendar) isWorkingDay(deptID primitive.ObjectID, date string) bool {
+	d, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return false
+	}
+	week, ok := wc.workdays[deptID]
+	if !ok {
+		week = weekdaySet(models.DefaultWorkDays)
+	}
+	if !week[d.Weekday()] {
+		return false
+	}
+	_, isHoliday := wc.holiday(deptID, date)
+	return !isHoliday
+}
+
+// Kullanıcının o günü kapsayan izni (yoksa nil).
+func (wc *workCalendar) leaveOn(userID primitive.ObjectID, date string) *models.Leave {
+	for i, l := range wc.leaves[userID] {
+		if l.From <= date && date <= l.To {
+			return &wc.leaves[userID][i]
+		}
+	}
+	return nil
+}
+
+// Kullanıcıdan o gün rapor beklenir mi? (çalışma günü ve izinli değil)
+func (wc *workCalendar) expected(u models.User, date string) bool {
+	return wc.isWorkingDay(u.DepartmentID, date) && wc.leaveOn(u.ID, date) == nil
+}
+
+// Uyum hesabı için: kullanıcı aktif, o tarihte kayıtlı ve o gün rapor bekleniyor.
+func (wc *workCalendar) expectedFrom(u models.User, date string) bool {
+	if !u.Active() || (!u.CreatedAt.IsZero() && date < u.CreatedAt.Local().Format("2006-01-02")) {
+		return false
+	}
+	return wc.expected(u, date)
+}
+
+// Aralıktaki çalışma dışı günler (hafta sonu, tatil).
+func (wc *workCalendar) nonWorkingDates(deptID primitive.ObjectID, from, to string) []string {
+	out := []string{}
+	eachDate(from, to, func(date string) {
+		if !wc.isWorkingDay(deptID, date) {
+			out = append(out, date)
+		}
+	})
+	return out
+}
+
+// Raporları (u = $lookup ile eşlenmiş kullanıcı) çalışma günlerine daraltan $match:
+// departmanın çalışma dışı günleri ve kullanıcı izinleri hariç tutulur.
+// deptID verilirse sadece o departmanın kuralları ve izinleri kullanılır.
+func (wc *workCalendar) workingReportsMatch(deptID *primitive.ObjectID, from, to string) bson.M {
+	var or []bson.M
+	if deptID != nil {
+		or = append(or, bson.M{"u.departmentId": *deptID, "date": bson.M{"$nin": wc.nonWorkingDates(*deptID, from, to)}})
+	} else {
+		known := make([]primitive.ObjectID, 0, len(wc.workdays))
+		for id := range wc.workdays {
+			known = append(known, id)
+			or = append(or, bson.M{"u.departmentId": id, "date": bson.M{"$nin": wc.nonWorkingDates(id, from, to)}})
+		}
+		or = append(or, bson.M{
+			"u.departmentId": bson.M{"$nin": known},
+			"date":           bson.M{"$nin": wc.nonWorkingDates(primitive.NilObjectID, from, to)},
+		})
+	}
+
+	var nor []bson.M
+	for uid, ls := range wc.leaves {
+		for _, l := range ls {
+			if deptID != nil && l.DepartmentID != *deptID {
+				continue
+			}
+			nor = append(nor, bson.M{"userId": uid, "date": bson.M{"$gte": max(l.From, from), "$lte": min(l.To, to)}})
+		}
+	}
+
+	match := bson.M{"$or": or}
+	if len(nor) > 0 {
+		match = bson.M{"$and": []bson.M{match, {"$nor": nor}}}
+	}
+	return match
+}
+
+// from..to (dahil, YYYY-MM-DD) her gün için fn çağırır.
+func eachDate(from, to string, fn func(date string)) {
+	d, err := time.ParseInLocation("2006-01-02", from, time.Local)
+	if err != nil {
+		return
+	}
+	for date := d.Format("2006-01-02"); date <= to; date = d.Format("2006-01-02") {
+		fn(date)
+		d = d.AddDate(0, 0, 1)
+	}
+}
+
+// ?from / ?to parametreleri; verilmezse bugünden itibaren def gün. Aralık en fazla bir yıl.
+func calendarRange(c *gin.Context, def int) (string, string, bool) {
+	from, to := strings.TrimSpace(c.Query("from")), strings.TrimSpace(c.Query("to"))
+	if from == "" {
+		from = todayStr()
+	}
+	f, err1 := time.ParseInLocation("2006-01-02", from, time.Local)
+	t := f.AddDate(0, 0, def-1)
+	var err2 error
+	if to != "" {
+		t, err2 = time.ParseInLocation("2006-01-02", to, time.Local)
+	}
+	if err1 != nil || err2 != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from/to must be YYYY-MM-DD"})
+		return "", "", false
+	}
+	if t.Before(f) || t.Sub(f) >= maxCalendarDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "range must be between 1 and 366 days"})
+		return "", "", false
+	}
+	return f.Format("2006-01-02"), t.Format("2006-01-02"), true
+}
+
+// Takvim görünümü için departman: superadmin istediğini (boşsa şirket varsayılanı),
+// diğerleri kendi departmanını görür.
+func calendarDepartment(c *gin.Context) (primitive.ObjectID, bool) {
+	if c.GetString("role") != string(models.RoleSuperAdmin) {
+		return myDepartmentID(c), true
+	}
+	dep := strings.TrimSpace(c.Query("department"))
+	if dep == "" {
+		return primitive.NilObjectID, true
+	}
+	d, err := resolveDepartment(c.Request.Context(), dep, false)
+	if err != nil {
+		departmentError(c, err)
+		return primitive.NilObjectID, false
+	}
+	return d.ID, true
+}
+
+// GET /api/calendar?from=YYYY-MM-DD&to=YYYY-MM-DD[&department=...]  (JWT)
+// Gün gün çalışma günü / tatil bilgisi (varsayılan: bugünden itibaren 30 gün).
+func GetWorkCalendar(c *gin.Context) {
+	from, to, ok := calendarRange(c, 30)
+	if !ok {
+		return
+	}
+	deptID, ok := calendarDepartment(c)
+	if !ok {
+		return
+	}
+	wc, err := loadWorkCalendar(c.Request.Context(), from, to, []primitive.ObjectID{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	type Day struct {
+		Date       string `json:"date"`
+		Weekday    int    `json:"weekday"`
+		WorkingDay bool   `json:"workingDay"`
+		Holiday    string `json:"holiday,omitempty"`
+	}
+	days := []Day{}
+	working := 0
+	eachDate(from, to, func(date string) {
+		d, _ := time.ParseInLocation("2006-01-02", date, time.Local)
+		h, _ := wc.holiday(deptID, date)
+		w := wc.isWorkingDay(deptID, date)
+		if w {
+			working++
+		}
+		days = append(days, Day{Date: date, Weekday: int(d.Weekday()), WorkingDay: w, Holiday: h})
+	})
+
+	week, ok := wc.workdays[deptID]
+	if !ok {
+		week = weekdaySet(models.DefaultWorkDays)
+	}
+	workDays := []int{}
+	for i, on := range week {
+		if on {
+			workDays = append(workDays, i)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"from":         from,
+		"to":           to,
+		"departmentId": hexOrEmpty(deptID),
+		"workDays":     workDays,
+		"workingDays":  working,
+		"days":         days,
+	})
+}
+
+// GET /api/calendar/holidays?from=&to=  (JWT; varsayılan bu yıl)
+// superadmin tüm tatilleri, diğerleri şirket geneli + kendi departmanınınkileri görür.
+func ListHolidays(c *gin.Context) {
+	ctx := c.Request.Context()
+	year := time.Now().Format("2006")
+	from, to := strings.TrimSpace(c.Query("from")), strings.TrimSpace(c.Query("to"))
+	if from == "" {
+		from = year + "-01-01"
+	}
+	if to == "" {
+		to = year + "-12-31"
+	}
+	filter := bson.M{"date": bson.M{"$gte": from, "$lte": to}}
+	if c.GetString("role") != string(models.RoleSuperAdmin) {
+		filter["$or"] = []bson.M{
+			{"departmentId": bson.M{"$exists": false}},
+			{"departmentId": myDepartmentID(c)},
+		}
+	}
+	cur, err := db.Col("holidays").Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "date", Value: 1}}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	items := []models.Holiday{}
+	if err := cur.All(ctx, &items); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// POST /api/calendar/holidays  {date, name, department?}  (superadmin)
+// department boşsa şirket geneli tatil.
+func CreateHoliday(c *gin.Context) {
+	ctx := c.Request.Context()
+	var body struct {
+		Date       string `json:"date"`
+		Name       string `json:"name"`
+		Department string `json:"department"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date and name required"})
+		return
+	}
+	d, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(body.Date), time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date must be YYYY-MM-DD"})
+		return
+	}
+
+	h := models.Holiday{
+		Date:      d.Format("2006-01-02"),
+		Name:      strings.TrimSpace(body.Name),
+		CreatedBy: toOID(c.GetString("userId")),
+		CreatedAt: time.Now().UTC(),
+	}
+	if strings.TrimSpace(body.Department) != "" {
+		dept, err := resolveDepartment(ctx, body.Department, true)
+		if err != nil {
+			departmentError(c, err)
+			return
+		}
+		h.DepartmentID = &dept.ID
+	}
+
+	res, err := db.Col("holidays").InsertOne(ctx, h)
+	if mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "holiday already exists for this date"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.ID, _ = res.InsertedID.(primitive.ObjectID)
+	c.JSON(http.StatusCreated, h)
+}
+
+// DELETE /api/calendar/holidays/:id  (superadmin)
+func DeleteHoliday(c *gin.Context) {
+	oid, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad id"})
+		return
+	}
+	res, err := db.Col("holidays").DeleteOne(c.Request.Context(), bson.M{"_id": oid})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if res.DeletedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// PUT /api/departments/:id/workweek  {workDays: [1,2,3,4,5]}  (admin kendi departmanı, superadmin)
+// 0 = Pazar ... 6 = Cumartesi. Boş liste varsayılan (Pazartesi-Cuma) takvime döner.
+func SetDepartmentWorkweek(c *gin.Context) {
+	ctx := c.Request.Context()
+	oid, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad id"})
+		return
+	}
+	if c.GetString("role") == string(models.RoleAdmin) && oid != myDepartmentID(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var body struct {
+		WorkDays []int `json:"workDays"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return
+	}
+	seen := map[int]bool{}
+	days := []int{}
+	for _, d := range body.WorkDays {
+		if d < 0 || d > 6 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "workDays must be weekdays 0 (Sunday) to 6 (Saturday)"})
+			return
+		}
+		if !seen[d] {
+			seen[d] = true
+			days = append(days, d)
+		}
+	}
+	sort.Ints(days)
+
+	update := bson.M{"$set": bson.M{"workDays": days}}
+	if len(days) == 0 {
+		update = bson.M{"$unset": bson.M{"workDays": ""}}
+	}
+	var d models.Department
+	err = db.Col("departments").FindOneAndUpdate(ctx, bson.M{"_id": oid}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&d)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "department not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": d.ID.Hex(), "name": d.Name, "workDays": d.Workweek()})
+}
+
+// İzin girme/silme yetkisi: herkes kendisi için; admin kendi departmanındaki
+// employee'ler için; superadmin herkes için.
+func canManageLeave(c *gin.Context, target models.User) bool {
+	switch c.GetString("role") {
+	case string(models.RoleSuperAdmin):
+		return true
+	case string(models.RoleAdmin):
+		if target.DepartmentID == myDepartmentID(c) && target.Role == models.RoleEmployee {
+			return true
+		}
+	}
+	return target.ID == toOID(c.GetString("userId"))
+}
+
+// GET /api/calendar/leaves?userId=&from=&to=  (JWT)
+// - employee: kendi izinleri
+// - admin: kendi departmanı
+// - superadmin: hepsi
+func ListLeaves(c *gin.Context) {
+	ctx := c.Request.Context()
+	filter := bson.M{}
+	switch c.GetString("role") {
+	case string(models.RoleSuperAdmin):
+	case string(models.RoleAdmin):
+		filter["departmentId"] = myDepartmentID(c)
+	default:
+		filter["userId"] = toOID(c.GetString("userId"))
+	}
+	if v := strings.TrimSpace(c.Query("userId")); v != "" && filter["userId"] == nil {
+		oid, err := primitive.ObjectIDFromHex(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad userId"})
+			return
+		}
+		filter["userId"] = oid
+	}
+	if v := strings.TrimSpace(c.Query("from")); v != "" {
+		filter["to"] = bson.M{"$gte": v}
+	}
+	if v := strings.TrimSpace(c.Query("to")); v != "" {
+		filter["from"] = bson.M{"$lte": v}
+	}
+
+	cur, err := db.Col("leaves").Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "from", Value: -1}}).SetLimit(500))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	items := []models.Leave{}
+	if err := cur.All(ctx, &items); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// POST /api/calendar/leaves  {userId?, from, to, type, note}  (JWT)
+// userId boşsa oturum sahibi. Aynı kullanıcı için çakışan izin girilemez.
+func CreateLeave(c *gin.Context) {
+	ctx := c.Request.Context()
+	var body struct {
+		UserID string `json:"userId"`
+		From   string `json:"from"`
+		To     string `json:"to"`
+		Type   string `json:"type"` // vacation|sick|other
+		Note   string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return
+	}
+
+	uid := toOID(c.GetString("userId"))
+	if v := strings.TrimSpace(body.UserID); v != "" {
+		oid, err := primitive.ObjectIDFromHex(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad userId"})
+			return
+		}
+		uid = oid
+	}
+	var target models.User
+	if err := db.Col("users").FindOne(ctx, bson.M{"_id": uid}).Decode(&target); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	if !canManageLeave(c, target) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	from, err1 := time.ParseInLocation("2006-01-02", strings.TrimSpace(body.From), time.Local)
+	to := from
+	var err2 error
+	if strings.TrimSpace(body.To) != "" {
+		to, err2 = time.ParseInLocation("2006-01-02", strings.TrimSpace(body.To), time.Local)
+	}
+	if err1 != nil || err2 != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from/to must be YYYY-MM-DD"})
+		return
+	}
+	if to.Before(from) || to.Sub(from) >= maxCalendarDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "leave must be between 1 and 366 days"})
+		return
+	}
+
+	typ := models.LeaveType(strings.ToLower(strings.TrimSpace(body.Type)))
+	switch typ {
+	case models.LeaveVacation, models.LeaveSick, models.LeaveOther:
+	case "":
+		typ = models.LeaveVacation
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be vacation, sick or other"})
+		return
+	}
+
+	l := models.Leave{
+		UserID:       target.ID,
+		UserName:     target.Name,
+		DepartmentID: target.DepartmentID,
+		From:         from.Format("2006-01-02"),
+		To:           to.Format("2006-01-02"),
+		Type:         typ,
+		Note:         strings.TrimSpace(body.Note),
+		CreatedBy:    toOID(c.GetString("userId")),
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	n, err := db.Col("leaves").CountDocuments(ctx, bson.M{
+		"userId": l.UserID,
+		"from":   bson.M{"$lte": l.To},
+		"to":     bson.M{"$gte": l.From},
+	}, options.Count().SetLimit(1))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if n > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "leave overlaps an existing leave"})
+		return
+	}
+
+	res, err := db.Col("leaves").InsertOne(ctx, l)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	l.ID, _ = res.InsertedID.(primitive.ObjectID)
+	c.JSON(http.StatusCreated, l)
+}
+
+// DELETE /api/calendar/leaves/:id  (JWT; girme yetkisiyle aynı)
+func DeleteLeave(c *gin.Context) {
+	ctx := c.Request.Context()
+	oid, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad id"})
+		return
+	}
+	var l models.Leave
+	if err := db.Col("leaves").FindOne(ctx, bson.M{"_id": oid}).Decode(&l); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	var target models.User
+	if err := db.Col("users").FindOne(ctx, bson.M{"_id": l.UserID}).Decode(&target); err != nil {
+		// kullanıcı silinmişse sadece superadmin temizleyebilir
+		target = models.User{ID: l.UserID}
+	}
+	if !canManageLeave(c, target) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	if _, err := db.Col("leaves").DeleteOne(ctx, bson.M{"_id": oid}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const maxCommentLength = 4000
+
+type commentNode struct {
+	models.ReportComment
+	Replies []*commentNode `json:"replies"`
+}
+
+// Düz listeyi (createdAt sıralı) ağaca çevirir; ebeveyni bulunamayan cevaplar köke eklenir.
+func buildCommentTree(items []models.ReportComment) []*commentNode {
+	nodes := make(map[primitive.ObjectID]*commentNode, len(items))
+	for _, it := range items {
+		nodes[it.ID] = &commentNode{ReportComment: it, Replies: []*commentNode{}}
+	}
+	roots := []*commentNode{}
+	for _, it := range items {
+		n := nodes[it.ID]
+		if it.ParentID != nil {
+			if p, ok := nodes[*it.ParentID]; ok {
+				p.Replies = append(p.Replies, n)
+				continue
+			}
+		}
+		roots = append(roots, n)
+	}
+	return roots
+}
+
+// Kullanıcı için raporun yorumlarını okunmuş işaretler.
+func markCommentsRead(ctx context.Context, uid, reportID primitive.ObjectID) error {
+	_, err := db.Col("comment_reads").UpdateOne(ctx,
+		bson.M{"userId": uid, "reportId": reportID},
+		bson.M{"$set": bson.M{"lastReadAt": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// GET /api/reports/:id/comments  (sahibi, departman admini, superadmin)
+// Yorumları ağaç halinde döner ve çağıran için okundu işaretler.
+func ListReportComments(c *gin.Context) {
+	ctx := c.Request.Context()
+	rep, ok := loadAccessibleReport(c)
+	if !ok {
+		return
+	}
+
+	cur, err := db.Col("report_comments").Find(ctx,
+		bson.M{"reportId": rep.ID},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	var items []models.ReportComment
+	if err := cur.All(ctx, &items); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	_ = markCommentsRead(ctx, toOID(c.GetString("userId")), rep.ID)
+
+	c.JSON(http.StatusOK, gin.H{"items": buildCommentTree(items), "count": len(items)})
+}
+
+// POST /api/reports/:id/comments  {body, parentId?}
+func CreateReportComment(c *gin.Context) {
+	ctx := c.Request.Context()
+	var body struct {
+		Body     string `json:"body"`
+		ParentID string `json:"parentId"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.Body) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "body required"})
+		return
+	}
+	text := strings.TrimSpace(body.Body)
+	if len(text) > maxCommentLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "comment too long"})
+		return
+	}
+
+	rep, ok := loadAccessibleReport(c)
+	if !ok {
+		return
+	}
+	me, err := currentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		return
+	}
+
+	cm := models.ReportComment{
+		ReportID:      rep.ID,
+		ReportOwnerID: rep.UserID,
+		AuthorID:      me.ID,
+		AuthorName:    me.Name,
+		AuthorRole:    me.Role,
+		Body:          text,
+		CreatedAt:     time.Now(),
+	}
+	if strings.TrimSpace(body.ParentID) != "" {
+		pid, err := primitive.ObjectIDFromHex(body.ParentID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad parentId"})
+			return
+		}
+		n, err := db.Col("report_comments").CountDocuments(ctx, bson.M{"_id": pid, "reportId": rep.ID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if n == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "parent comment not found"})
+			return
+		}
+		cm.ParentID = &pid
+	}
+
+	res, err := db.Col("report_comments").InsertOne(ctx, cm)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	cm.ID, _ = res.InsertedID.(primitive.ObjectID)
+
+	// yazan kendi yorumunu okunmamış görmesin
+	_ = markCommentsRead(ctx, me.ID, rep.ID)
+
+	c.JSON(http.StatusCreated, cm)
+}
+
+// :commentId yorumunu getirir; sadece yazarı değiştirebilir.
+func loadOwnComment(c *gin.Context) (models.ReportComment, bool) {
+	rep, ok := loadAccessibleReport(c)
+	if !ok {
+		return models.ReportComment{}, false
+	}
+	cid, err := primitive.ObjectIDFromHex(c.Param("commentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad comment id"})
+		return models.ReportComment{}, false
+	}
+	var cm models.ReportComment
+	err = db.Col("report_comments").FindOne(c.Request.Context(), bson.M{"_id": cid, "reportId": rep.ID}).Decode(&cm)
+	if err == mongo.ErrNoDocuments || (err == nil && cm.DeletedAt != nil) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "comment not found"})
+		return models.ReportComment{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return models.ReportComment{}, false
+	}
+	if cm.AuthorID.Hex() != c.GetString("userId") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "you can only change your own comments"})
+		return models.ReportComment{}, false
+	}
+	return cm, true
+}
+
+// PATCH /api/reports/:id/comments/:commentId  {body}
+func UpdateReportComment(c *gin.Context) {
+	var body struct {
+		Body string `json:"body"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.Body) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "body required"})
+		return
+	}
+	text := strings.TrimSpace(body.Body)
+	if len(text) > maxCommentLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "comment too long"})
+		return
+	}
+	cm, ok := loadOwnComment(c)
+	if !ok {
+		return
+	}
+
+	err := db.Col("report_comments").FindOneAndUpdate(c.Request.Context(),
+		bson.M{"_id": cm.ID},
+		bson.M{"$set": bson.M{"body": text, "editedAt": time.Now()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&cm)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cm)
+}
+
+// DELETE /api/reports/:id/comments/:commentId
+// Yumuşak silme: cevaplar yerinde kalır, içerik boşaltılır.
+func DeleteReportComment(c *gin.Context) {
+	cm, ok := loadOwnComment(c)
+	if !ok {
+		return
+	}
+	if _, err := db.Col("report_comments").UpdateByID(c.Request.Context(), cm.ID,
+		bson.M{"$set": bson.M{"body": "", "deletedAt": time.Now()}},
+	); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+type commentCounts struct {
+	Total  int
+	Unread int
+}
+
+// Sahibin raporlarındaki silinmemiş yorumların rapor başına toplam sayısı ve
+// başkalarının yazıp sahibin henüz okumadığı yorum sayısı. reportIDs nil ise sahibin tüm raporları.
+func ownerCommentCounts(ctx context.Context, owner primitive.ObjectID, reportIDs []primitive.ObjectID) (map[primitive.ObjectID]commentCounts, error) {
+	match := bson.M{
+		"reportOwnerId": owner,
+		"deletedAt":     bson.M{"$exists": false},
+	}
+	if reportIDs != nil {
+		match["reportId"] = bson.M{"$in": reportIDs}
+	}
+	cur, err := db.Col("report_comments").Aggregate(ctx, []bson.M{
+		{"$match": match},
+		{"$lookup": bson.M{
+			"from": "comment_reads",
+			"let":  bson.M{"rid": "$reportId"},
+			"pipeline": []bson.M{
+				{"$match": bson.M{"$expr": bson.M{"$and": []bson.M{
+					{"$eq": []interface{}{"$reportId", "$$rid"}},
+					{"$eq": []interface{}{"$userId", owner}},
+				}}}},
+			},
+			"as": "r",
+		}},
+		{"$group": bson.M{
+			"_id":   "$reportId",
+			"total": bson.M{"$sum": 1},
+			"unread": bson.M{"$sum": bson.M{"$cond": []interface{}{
+				bson.M{"$and": []bson.M{
+					{"$ne": []interface{}{"$authorId", owner}},
+					{"$gt": []interface{}{"$createdAt", bson.M{"$ifNull": []interface{}{bson.M{"$arrayElemAt": []interface{}{"$r.lastReadAt", 0}}, time.Time{}}}}},
+				}},
+				1, 0,
+			}}},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		ID     primitive.ObjectID `bson:"_id"`
+		Total  int                `bson:"total"`
+		Unread int                `bson:"unread"`
+	}
+	if err := cur.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	out := make(map[primitive.ObjectID]commentCounts, len(rows))
+	for _, r := range rows {
+		out[r.ID] = commentCounts{Total: r.Total, Unread: r.Unread}
+	}
+	return out, nil
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type complianceRow struct {
+	UserID       string   `json:"userId"`
+	Name         string   `json:"name"`
+	Department   string   `json:"department,omitempty"`
+	ExpectedDays int      `json:"expectedDays"` // çalışma günü, izinli değil
+	ReportedDays int      `json:"reportedDays"` // beklenen günlerden rapor gönderilenler (taslak hariç)
+	MissedDays   int      `json:"missedDays"`
+	LateDays     int      `json:"lateDays"`       // rapor, tarihinden sonraki bir günde oluşturulmuş
+	Rate         *float64 `json:"complianceRate"` // yüzde; beklenen gün yoksa null
+	// Ardışık raporlanan beklenen günler (hafta sonu, tatil ve izin seriyi bozmaz)
+	CurrentStreak int `json:"currentStreak"`
+	LongestStreak int `json:"longestStreak"`
+}
+
+// GET /api/reports/compliance?department=&from=YYYY-MM-DD&to=YYYY-MM-DD&sort=rate|-rate|missed|late|name&limit=
+// (admin: kendi departmanı, superadmin: departman ya da boşsa tüm şirket)
+// Varsayılan aralık son 30 gün; sıralama varsayılanı en düşük uyum oranı önce.
+func GetReportCompliance(c *gin.Context) {
+	ctx := c.Request.Context()
+	role := c.GetString("role")
+	today := todayStr()
+
+	// --- aralık ---
+	to := strings.TrimSpace(c.Query("to"))
+	if to == "" || to > today {
+		to = today
+	}
+	toT, err := time.ParseInLocation("2006-01-02", to, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be YYYY-MM-DD"})
+		return
+	}
+	from := strings.TrimSpace(c.Query("from"))
+	if from == "" {
+		from = toT.AddDate(0, 0, -29).Format("2006-01-02")
+	}
+	fromT, err := time.ParseInLocation("2006-01-02", from, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be YYYY-MM-DD"})
+		return
+	}
+	if toT.Before(fromT) || toT.Sub(fromT) >= maxCalendarDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "range must be between 1 and 366 days"})
+		return
+	}
+	from, to = fromT.Format("2006-01-02"), toT.Format("2006-01-02")
+
+	// --- kapsam ---
+	dep := strings.TrimSpace(c.Query("department"))
+	userFilter := activeUserFilter()
+	if role == string(models.RoleAdmin) {
+		if myDepartmentID(c).IsZero() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "user has no department"})
+			return
+		}
+		dep = c.GetString("department")
+		userFilter["departmentId"] = myDepartmentID(c)
+	} else if dep != "" {
+		d, err := resolveDepartment(ctx, dep, false)
+		if err != nil {
+			departmentError(c, err)
+			return
+		}
+		dep = d.Name
+		userFilter["departmentId"] = d.ID
+	}
+	// superadmin'ler rapor yazmaz
+	userFilter["role"] = bson.M{"$ne": models.RoleSuperAdmin}
+
+	ucur, err := db.Col("users").Find(ctx, userFilter, options.Find().SetProjection(bson.M{
+		"_id": 1, "name": 1, "departmentId": 1, "department": 1, "createdAt": 1, "deactivatedAt": 1,
+	}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	var users []models.User
+	if err := ucur.All(ctx, &users); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ids := make([]primitive.ObjectID, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+
+	wc, err := loadWorkCalendar(ctx, from, to, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// --- raporlar: kullanıcı -> tarih -> geç mi? (taslaklar sayılmaz) ---
+	reported := map[primitive.ObjectID]map[string]bool{}
+	if len(ids) > 0 {
+		rcur, err := db.Col("reports").Find(ctx, bson.M{
+			"userId": bson.M{"$in": ids},
+			"date":   bson.M{"$gte": from, "$lte": to},
+			"status": bson.M{"$ne": models.ReportDraft},
+		}, options.Find().SetProjection(bson.M{"userId": 1, "date": 1, "createdAt": 1, "importBatchId": 1}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		for rcur.Next(ctx) {
+			var r models.Report
+			if err := rcur.Decode(&r); err != nil {
+				continue
+			}
+			if reported[r.UserID] == nil {
+				reported[r.UserID] = map[string]bool{}
+			}
+			// içe aktarılan raporların createdAt'i aktarım zamanıdır; geç sayılmaz
+			late := r.ImportBatchID == nil && !r.CreatedAt.IsZero() && r.CreatedAt.Local().Format("2006-01-02") > r.Date
+			reported[r.UserID][r.Date] = late
+		}
+		_ = rcur.Close(ctx)
+	}
+
+	// --- kullanıcı başına sayım ve seriler ---
+	items := make([]complianceRow, 0, len(users))
+	var sumExpected, sumReported, sumLate int
+	for _, u := range users {
+		row := complianceRow{UserID: u.ID.Hex(), Name: u.Name, Department: u.Department}
+		run := 0
+		eachDate(from, to, func(date string) {
+			if !wc.expectedFrom(u, date) {
+				return
+			}
+			late, ok := reported[u.ID][date]
+			if !ok && date == today {
+				// gün bitmedi: bugünkü eksik rapor seriyi bozmaz
+				return
+			}
+			row.ExpectedDays++
+			if !ok {
+				row.MissedDays++
+				run = 0
+				return
+			}
+			row.ReportedDays++
+			if late {
+				row.LateDays++
+			}
+			run++
+			row.LongestStreak = max(row.LongestStreak, run)
+		})
+		row.CurrentStreak = run
+		if row.ExpectedDays > 0 {
+			rate := math.Round(float64(row.ReportedDays)/float64(row.ExpectedDays)*1000) / 10
+			row.Rate = &rate
+		}
+		sumExpected += row.ExpectedDays
+		sumReported += row.ReportedDays
+		sumLate += row.LateDays
+		items = append(items, row)
+	}
+
+	sortComplianceRows(items, strings.TrimSpace(c.Query("sort")))
+
+	total := len(items)
+	if v := c.Query("limit"); v != "" {
+		if n, e := strconv.ParseInt(v, 10, 64); e == nil && n > 0 && int(n) < len(items) {
+			items = items[:n]
+		}
+	}
+
+	rate := 0.0
+	if sumExpected > 0 {
+		rate = math.Round(float64(sumReported)/float64(sumExpected)*1000) / 10
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"from":       from,
+		"to":         to,
+		"department": dep,
+		"items":      items,
+		"summary": gin.H{
+			"users":          total,
+			"expectedDays":   sumExpected,
+			"reportedDays":   sumReported,
+			"missedDays":     sumExpected - sumReported,
+			"lateDays":       sumLate,
+			"complianceRate": rate,
+		},
+	})
+}
+
+// rate (varsayılan): en düşük uyum önce; -rate: en yüksek önce; missed/late: en çok önce; name.
+// Beklenen günü olmayanlar (oran null) her zaman sonda.
+func sortComplianceRows(items []complianceRow, by string) {
+	rateOf := func(r complianceRow) float64 {
+		if r.Rate == nil {
+			return math.Inf(1)
+		}
+		return *r.Rate
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		switch by {
+		case "name":
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		case "missed":
+			if a.MissedDays != b.MissedDays {
+				return a.MissedDays > b.MissedDays
+			}
+		case "late":
+			if a.LateDays != b.LateDays {
+				return a.LateDays > b.LateDays
+			}
+		case "-rate":
+			if (a.Rate == nil) != (b.Rate == nil) {
+				return b.Rate == nil
+			}
+			if rateOf(a) != rateOf(b) {
+				return rateOf(a) > rateOf(b)
+			}
+		default:
+			if rateOf(a) != rateOf(b) {
+				return rateOf(a) < rateOf(b)
+			}
+			if a.MissedDays != b.MissedDays {
+				return a.MissedDays > b.MissedDays
+			}
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+}
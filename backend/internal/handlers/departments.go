@@ -1,30 +1,90 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"report-management-system/internal/db"
+	"report-management-system/internal/middleware"
 	"report-management-system/internal/models"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+var (
+	errUnknownDepartment  = errors.New("unknown department")
+	errArchivedDepartment = errors.New("department is archived")
+)
+
+// Departmanı ID (hex) ya da ada göre (büyük/küçük harf duyarsız) bulur.
+// activeOnly: yazma işlemleri (kayıt, davet, hatırlatma) için arşivlenmişleri reddeder.
+func resolveDepartment(ctx context.Context, ref string, activeOnly bool) (models.Department, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return models.Department{}, errUnknownDepartment
+	}
+
+	var d models.Department
+	var err error
+	if oid, e := primitive.ObjectIDFromHex(ref); e == nil {
+		err = db.Col("departments").FindOne(ctx, bson.M{"_id": oid}).Decode(&d)
+	} else {
+		err = db.Col("departments").FindOne(ctx, bson.M{"name": ref},
+			options.FindOne().SetCollation(&options.Collation{Locale: "en", Strength: 2}),
+		).Decode(&d)
+	}
+	if err == mongo.ErrNoDocuments {
+		return models.Department{}, errUnknownDepartment
+	}
+	if err != nil {
+		return models.Department{}, err
+	}
+	if activeOnly && !d.Active {
+		return models.Department{}, errArchivedDepartment
+	}
+	return d, nil
+}
+
+// resolveDepartment hatasını uygun HTTP yanıtına çevirir.
+func departmentError(c *gin.Context, err error) {
+	switch err {
+	case errUnknownDepartment, errArchivedDepartment:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// Oturum sahibinin departman ID'si (middleware set eder); yoksa NilObjectID.
+func myDepartmentID(c *gin.Context) primitive.ObjectID {
+	return toOID(c.GetString("departmentId"))
+}
+
+// GET /api/departments[?includeArchived=1]
+// - superadmin: aktif departman adları + tam kayıtlar ("items")
+// - admin / employee: sadece kendi departmanı
 func GetDepartments(c *gin.Context) {
 	ctx := c.Request.Context()
 	role := c.GetString("role")
 
 	if role == string(models.RoleSuperAdmin) {
-		// departments koleksiyonundan aktif olanları sırala
+		filter := bson.M{"active": true}
+		if c.Query("includeArchived") == "1" {
+			filter = bson.M{}
+		}
+
+		// departments koleksiyonundan sırala
 		cur, err := db.Col("departments").Find(
 			ctx,
-			bson.M{"active": true},
-			options.Find().
-				SetProjection(bson.M{"name": 1, "_id": 0}).
-				SetSort(bson.D{{Key: "name", Value: 1}}),
+			filter,
+			options.Find().SetSort(bson.D{{Key: "name", Value: 1}}),
 		)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -32,38 +92,263 @@ func GetDepartments(c *gin.Context) {
 		}
 		defer cur.Close(ctx)
 
-		type Row struct {
-			Name string `bson:"name"`
-		}
 		out := []string{}
+		items := []models.Department{}
 		for cur.Next(ctx) {
-			var r Row
-			_ = cur.Decode(&r)
-			if d := strings.TrimSpace(r.Name); d != "" {
-				out = append(out, d)
+			var d models.Department
+			if err := cur.Decode(&d); err != nil {
+				continue
+			}
+			items = append(items, d)
+			if name := strings.TrimSpace(d.Name); name != "" && d.Active {
+				out = append(out, name)
 			}
 		}
-		c.JSON(http.StatusOK, gin.H{"departments": out})
+		c.JSON(http.StatusOK, gin.H{"departments": out, "items": items})
 		return
 	}
 
 	// admin / employee: sadece kendi departmanı
-	uid := c.GetString("userId")
-	oid, err := primitive.ObjectIDFromHex(uid)
+	dep := c.GetString("department")
+	if dep == "" {
+		c.JSON(http.StatusOK, gin.H{"departments": []string{}})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"departments": []string{dep}})
+}
+
+// POST /api/departments  {name}  (superadmin)
+func CreateDepartment(c *gin.Context) {
+	var body struct {
+		Name string `json:"name"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name required"})
+		return
+	}
+
+	// "sales" / "Sales" gibi sadece harf farkı olan kopyaları engelle
+	if _, err := resolveDepartment(c.Request.Context(), body.Name, false); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "department already exists"})
+		return
+	} else if err != errUnknownDepartment {
+		departmentError(c, err)
+		return
+	}
+
+	d := models.Department{
+		Name:      strings.TrimSpace(body.Name),
+		Active:    true,
+		CreatedAt: time.Now().UTC(),
+	}
+	res, err := db.Col("departments").InsertOne(c.Request.Context(), d)
+	if mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "department already exists"})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "bad user id"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	d.ID, _ = res.InsertedID.(primitive.ObjectID)
+	c.JSON(http.StatusCreated, d)
+}
 
-	var u models.User
-	if err := db.Col("users").FindOne(ctx, bson.M{"_id": oid}).Decode(&u); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+func loadDepartment(c *gin.Context) (models.Department, bool) {
+	oid, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad id"})
+		return models.Department{}, false
+	}
+	var d models.Department
+	if err := db.Col("departments").FindOne(c.Request.Context(), bson.M{"_id": oid}).Decode(&d); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return models.Department{}, false
+	}
+	return d, true
+}
+
+// PATCH /api/departments/:id  {name}  (superadmin)
+// Referanslar ID ile tutulur; denormalize adlar (users.department,
+// reminders.targetDepartment(s), reminder_series.template, invites.department,
+// projects.department) departman kaydından sonra departmentId ile güncellenir.
+// Transaction kullanılmaz (standalone MongoDB'de de çalışır): her adım tekrar
+// çalıştırılabilir, yarıda kalan bir yeniden adlandırma aynı adla tekrar
+// istenerek tamamlanır.
+func RenameDepartment(c *gin.Context) {
+	ctx := c.Request.Context()
+	var body struct {
+		Name string `json:"name"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name required"})
 		return
 	}
-	dep := strings.TrimSpace(u.Department)
-	if dep == "" {
-		c.JSON(http.StatusOK, gin.H{"departments": []string{}})
+	d, ok := loadDepartment(c)
+	if !ok {
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"departments": []string{dep}})
+	newName := strings.TrimSpace(body.Name)
+	if other, err := resolveDepartment(ctx, newName, false); err == nil && other.ID != d.ID {
+		c.JSON(http.StatusConflict, gin.H{"error": "department already exists"})
+		return
+	}
+
+	if newName != d.Name {
+		_, err := db.Col("departments").UpdateByID(ctx, d.ID, bson.M{"$set": bson.M{"name": newName}})
+		if mongo.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "department already exists"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	users, reminders, err := cascadeDepartmentName(ctx, d.ID, newName)
+	// kullanıcıların departmanı değişti (yarıda kalsa da bir kısmı değişmiş olabilir)
+	middleware.InvalidateAllUsers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	d.Name = newName
+	c.JSON(http.StatusOK, gin.H{
+		"department":       d,
+		"usersUpdated":     users,
+		"remindersUpdated": reminders,
+	})
+}
+
+// Departmanın denormalize adını ona ID ile bağlı kayıtlara yazar. Filtreler
+// eski ada değil departmentId'ye bakar; tekrar çalıştırmak güvenlidir.
+func cascadeDepartmentName(ctx context.Context, id primitive.ObjectID, name string) (users, reminders int64, err error) {
+	ur, err := db.Col("users").UpdateMany(ctx,
+		bson.M{"departmentId": id, "department": bson.M{"$ne": name}},
+		bson.M{"$set": bson.M{"department": name}},
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+	users = ur.ModifiedCount
+
+	rr, err := db.Col("reminders").UpdateMany(ctx,
+		bson.M{"targetDepartmentId": id, "targetDepartment": bson.M{"$ne": name}},
+		bson.M{"$set": bson.M{"targetDepartment": name}},
+	)
+	if err != nil {
+		return users, 0, err
+	}
+	reminders = rr.ModifiedCount
+
+	// çok hedefli hatırlatmalardaki ad listesi
+	if _, err := db.Col("reminders").UpdateMany(ctx,
+		bson.M{"targetDepartmentIds": id},
+		renameInTargetList("targetDepartmentIds", "targetDepartments", id, name),
+	); err != nil {
+		return users, reminders, err
+	}
+	// zamanlanmış/tekrarlı hatırlatma şablonları
+	if _, err := db.Col("reminder_series").UpdateMany(ctx,
+		bson.M{"template.targetDepartmentId": id, "template.targetDepartment": bson.M{"$ne": name}},
+		bson.M{"$set": bson.M{"template.targetDepartment": name}},
+	); err != nil {
+		return users, reminders, err
+	}
+	if _, err := db.Col("reminder_series").UpdateMany(ctx,
+		bson.M{"template.targetDepartmentIds": id},
+		renameInTargetList("template.targetDepartmentIds", "template.targetDepartments", id, name),
+	); err != nil {
+		return users, reminders, err
+	}
+	for _, col := range []string{"invites", "projects"} {
+		if _, err := db.Col(col).UpdateMany(ctx,
+			bson.M{"departmentId": id, "department": bson.M{"$ne": name}},
+			bson.M{"$set": bson.M{"department": name}},
+		); err != nil {
+			return users, reminders, err
+		}
+	}
+	return users, reminders, nil
+}
+
+// targetDepartments, targetDepartmentIds ile aynı sıradadır: departmanın
+// ID'sinin bulunduğu indisteki ad yazılır (pipeline update, MongoDB 4.2+).
+func renameInTargetList(idsField, namesField string, id primitive.ObjectID, name string) bson.A {
+	ids, names := "$"+idsField, "$"+namesField
+	return bson.A{bson.M{"$set": bson.M{namesField: bson.M{"$map": bson.M{
+		"input": bson.M{"$range": bson.A{0, bson.M{"$size": ids}}},
+		"as":    "i",
+		"in": bson.M{"$cond": bson.A{
+			bson.M{"$eq": bson.A{bson.M{"$arrayElemAt": bson.A{ids, "$$i"}}, id}},
+			name,
+			bson.M{"$arrayElemAt": bson.A{names, "$$i"}},
+		}},
+	}}}}}
+}
+
+// POST /api/departments/:id/archive  (superadmin)
+// Arşivlenen departman listelerde ve şirket analitiğinde görünmez; raporları
+// (ve kullanıcıların departman bilgisi) korunur, arama ile erişilebilir.
+func ArchiveDepartment(c *gin.Context) {
+	setDepartmentActive(c, false)
+}
+
+// POST /api/departments/:id/restore  (superadmin)
+func RestoreDepartment(c *gin.Context) {
+	setDepartmentActive(c, true)
+}
+
+func setDepartmentActive(c *gin.Context, active bool) {
+	d, ok := loadDepartment(c)
+	if !ok {
+		return
+	}
+
+	update := bson.M{"$set": bson.M{"active": true}, "$unset": bson.M{"archivedAt": ""}}
+	if !active {
+		update = bson.M{"$set": bson.M{"active": false, "archivedAt": time.Now().UTC()}}
+	}
+
+	if err := db.Col("departments").FindOneAndUpdate(c.Request.Context(), bson.M{"_id": d.ID}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&d); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, d)
+}
+
+// DELETE /api/departments/:id  (superadmin)
+// Sadece hiç kullanıcısı / projesi olmayan departman silinebilir; aksi halde arşivleyin.
+func DeleteDepartment(c *gin.Context) {
+	ctx := c.Request.Context()
+	d, ok := loadDepartment(c)
+	if !ok {
+		return
+	}
+
+	n, err := db.Col("users").CountDocuments(ctx, bson.M{"departmentId": d.ID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if n > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "department has users; archive it instead", "users": n})
+		return
+	}
+	if n, err := db.Col("projects").CountDocuments(ctx, bson.M{"departmentId": d.ID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	} else if n > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "department has projects; archive it instead", "projects": n})
+		return
+	}
+
+	if _, err := db.Col("departments").DeleteOne(ctx, bson.M{"_id": d.ID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
 }
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/export"
+	"report-management-system/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Zaman çizelgesi sütunları (genişlikler PDF için pt; toplam = A4 yatay - kenar boşlukları).
+var reportExportColumns = []export.Column{
+	{Title: "Date", Width: 65},
+	{Title: "Employee", Width: 110},
+	{Title: "Department", Width: 95},
+	{Title: "Hours", Width: 40},
+	{Title: "Status", Width: 75},
+	{Title: "Content", Width: 385},
+}
+
+// İş kalemli raporlarda içerik kalem listesi olarak yazılır.
+func exportContent(r models.Report) string {
+	if len(r.Entries) == 0 {
+		return r.Content
+	}
+	lines := make([]string, 0, len(r.Entries))
+	for _, e := range r.Entries {
+		meta := []string{fmt.Sprintf("%gh", e.Hours)}
+		if e.Project != "" {
+			meta = append(meta, e.Project)
+		}
+		if e.Category != "" {
+			meta = append(meta, e.Category)
+		}
+		if e.Status != "" {
+			meta = append(meta, string(e.Status))
+		}
+		lines = append(lines, "- "+e.Description+" ("+strings.Join(meta, ", ")+")")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Eşleşen raporları cursor üzerinden satır satır istemciye akıtır (cur.All ile belleğe almadan).
+// Akış başladıktan sonra oluşan hatalar yanıt koduna yansıtılamaz; loglanır ve çıktı kesilir.
+func streamReportExport(c *gin.Context, format export.Format, name, title string, match bson.M) {
+	ctx := c.Request.Context()
+	cur, err := db.Col("reports").Aggregate(ctx, []bson.M{
+		{"$match": match},
+		{"$sort": bson.D{{Key: "date", Value: 1}, {Key: "userName", Value: 1}, {Key: "_id", Value: 1}}},
+		{"$lookup": bson.M{
+			"from":         "users",
+			"localField":   "userId",
+			"foreignField": "_id",
+			"as":           "u",
+		}},
+		{"$addFields": bson.M{
+			"userName":   bson.M{"$ifNull": []interface{}{bson.M{"$arrayElemAt": []interface{}{"$u.name", 0}}, "$userName"}},
+			"department": bson.M{"$arrayElemAt": []interface{}{"$u.department", 0}},
+		}},
+		{"$project": bson.M{"u": 0}},
+	}, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer cur.Close(ctx)
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment",
+		map[string]string{"filename": sanitizeFileName(name + format.Ext())}))
+	c.Header("Cache-Control", "private, no-store")
+	c.Status(http.StatusOK)
+
+	w := export.New(format, c.Writer, title)
+	fail := func(err error) {
+		log.Printf("report export (%s): %v", format, err)
+		_ = c.Error(err)
+	}
+	if err := w.Header(reportExportColumns); err != nil {
+		fail(err)
+		return
+	}
+
+	var total float64
+	rows := 0
+	for cur.Next(ctx) {
+		var row struct {
+			models.Report `bson:",inline"`
+			Department    string `bson:"department"`
+		}
+		if err := cur.Decode(&row); err != nil {
+			fail(err)
+			return
+		}
+		total += row.Hours
+		rows++
+		if err := w.Row([]any{
+			row.Date, row.UserName, row.Department, row.Hours,
+			string(row.EffectiveStatus()), exportContent(row.Report),
+		}); err != nil {
+			fail(err) // istemci bağlantıyı kapatmış olabilir
+			return
+		}
+	}
+	if err := cur.Err(); err != nil {
+		fail(err)
+		return
+	}
+	if err := w.Row([]any{"Total", fmt.Sprintf("%d report(s)", rows), "", total, "", ""}); err != nil {
+		fail(err)
+		return
+	}
+	if err := w.Close(); err != nil {
+		fail(err)
+	}
+}
+
+func exportFormat(c *gin.Context) (export.Format, bool) {
+	f, err := export.ParseFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv, xlsx or pdf"})
+		return "", false
+	}
+	return f, true
+}
+
+// Dosya adı için tarih aralığı eki: "_2025-01-01_2025-01-31"
+func exportRangeSuffix(from, to string) string {
+	s := ""
+	if from != "" {
+		s += "_" + from
+	}
+	if to != "" {
+		s += "_" + to
+	}
+	return s
+}
+
+// GET /api/reports/export?format=csv|xlsx|pdf&...  (admin/superadmin)
+// Filtreler SearchReports ile aynıdır (q, department, user, role, minHours, maxHours,
+// hasAttachments, status, project, from, to); admin her zaman kendi departmanıyla sınırlıdır.
+// Sonuç tarih sırasıyla, sayfalama olmadan akıtılır.
+func ExportReports(c *gin.Context) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+	params := c.Request.URL.Query()
+	match, empty, err := buildSearchMatch(c.Request.Context(), params, requestSearchScope(c))
+	if err != nil {
+		searchMatchError(c, err)
+		return
+	}
+	if empty {
+		// hiçbir kullanıcıyla eşleşmiyor: boş (ama geçerli) dosya
+		match = bson.M{"_id": primitive.NilObjectID}
+	}
+	name := "reports" + exportRangeSuffix(strings.TrimSpace(params.Get("from")), strings.TrimSpace(params.Get("to")))
+	streamReportExport(c, format, name, "Timesheet", match)
+}
+
+// GET /api/reports/user/:id/export?format=csv|xlsx|pdf&from=YYYY-MM-DD&to=YYYY-MM-DD  (admin/superadmin)
+// Erişim GetUserReports ile aynı.
+func ExportUserReports(c *gin.Context) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+	uid, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad user id"})
+		return
+	}
+	var u models.User
+	if err := db.Col("users").FindOne(c.Request.Context(), bson.M{"_id": uid}).Decode(&u); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	from := strings.TrimSpace(c.Query("from"))
+	to := strings.TrimSpace(c.Query("to"))
+	match := bson.M{"userId": uid}
+	if from != "" || to != "" {
+		dc := bson.M{}
+		if from != "" {
+			dc["$gte"] = from
+		}
+		if to != "" {
+			dc["$lte"] = to
+		}
+		match["date"] = dc
+	}
+	streamReportExport(c, format, "reports_"+u.Name+exportRangeSuffix(from, to), "Timesheet - "+u.Name, match)
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"report-management-system/internal/importer"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const defaultImportMaxBytes = 50 << 20 // 50 MB
+
+// IMPORT_MAX_BYTES: yüklenen CSV üst sınırı (bayt)
+func importMaxBytes() int64 {
+	if v := strings.TrimSpace(os.Getenv("IMPORT_MAX_BYTES")); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultImportMaxBytes
+}
+
+func formBool(c *gin.Context, key string) bool {
+	b, _ := strconv.ParseBool(strings.TrimSpace(c.PostForm(key)))
+	return b
+}
+
+// POST /api/imports/users | /api/imports/reports  (superadmin, multipart)
+//
+//	file:        CSV (ilk satır başlık)
+//	mapping:     {"alan": "CSV başlığı", ...} JSON (opsiyonel; varsayılan başlık = alan adı)
+//	dateFormat:  Go layout, örn. 02.01.2006 (reports; varsayılan 2006-01-02)
+//	dryRun:      true → sadece doğrula, satır hatalarını döndür
+//	skipInvalid: true → geçersiz satırları atla, geçerlileri yaz
+//
+// Geçersiz satır varsa ve skipInvalid kapalıysa hiçbir şey yazılmaz (422 + satır hataları).
+func importHandler(run func(context.Context, io.Reader, importer.Options) (*importer.Result, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, importMaxBytes())
+
+		fh, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file required (or request too large)"})
+			return
+		}
+		opt := importer.Options{
+			DateFormat:  strings.TrimSpace(c.PostForm("dateFormat")),
+			DryRun:      formBool(c, "dryRun"),
+			SkipInvalid: formBool(c, "skipInvalid"),
+			Source:      sanitizeFileName(fh.Filename),
+			CreatedBy:   toOID(c.GetString("userId")),
+		}
+		if raw := strings.TrimSpace(c.PostForm("mapping")); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &opt.Mapping); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "mapping must be a JSON object of field → column"})
+				return
+			}
+		}
+
+		f, err := fh.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer f.Close()
+
+		res, err := run(c.Request.Context(), f, opt)
+		switch {
+		case errors.Is(err, importer.ErrInvalidRows):
+			c.JSON(http.StatusUnprocessableEntity, res)
+		case errors.Is(err, importer.ErrBadInput):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "result": res})
+		default:
+			c.JSON(http.StatusOK, res)
+		}
+	}
+}
+
+var (
+	ImportUsers   = importHandler(importer.ImportUsers)
+	ImportReports = importHandler(importer.ImportReports)
+)
+
+// GET /api/imports  (superadmin) — son içe aktarma işleri
+func ListImports(c *gin.Context) {
+	items, err := importer.ListBatches(c.Request.Context(), 100)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// POST /api/imports/:id/rollback  (superadmin)
+func RollbackImport(c *gin.Context) {
+	oid, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad id"})
+		return
+	}
+	res, err := importer.Rollback(c.Request.Context(), oid, toOID(c.GetString("userId")))
+	switch {
+	case errors.Is(err, importer.ErrBatchNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, importer.ErrAlreadyRolledBack), errors.Is(err, importer.ErrUsersHaveReports):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusOK, res)
+	}
+}
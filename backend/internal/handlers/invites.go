@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultInviteTTL = 72 * time.Hour
+	maxInviteTTL     = 30 * 24 * time.Hour
+)
+
+// ALLOW_OPEN_REGISTRATION=true ise davetsiz kayıt açık (rol her zaman employee).
+func openRegistrationEnabled() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("ALLOW_OPEN_REGISTRATION"))) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
+
+type inviteDTO struct {
+	models.Invite
+	Status string `json:"status"`
+}
+
+// POST /api/invites  (admin|superadmin)
+// - superadmin: her rol, her departman
+// - admin: sadece employee, sadece kendi departmanı
+func CreateInvite(c *gin.Context) {
+	var body struct {
+		Email          string `json:"email"`          // optional
+		Role           string `json:"role"`           // default: employee
+		Department     string `json:"department"`     // superadmin için (ad veya ID)
+		ExpiresInHours int    `json:"expiresInHours"` // default: 72
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return
+	}
+
+	me, err := currentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		return
+	}
+
+	role := models.RoleEmployee
+	switch strings.ToLower(strings.TrimSpace(body.Role)) {
+	case "", "employee":
+	case "admin":
+		role = models.RoleAdmin
+	case "superadmin":
+		role = models.RoleSuperAdmin
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid role"})
+		return
+	}
+
+	deptRef := strings.TrimSpace(body.Department)
+	if me.Role == models.RoleAdmin {
+		if role != models.RoleEmployee {
+			c.JSON(http.StatusForbidden, gin.H{"error": "admins can only invite employees"})
+			return
+		}
+		if me.DepartmentID.IsZero() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "admin has no department"})
+			return
+		}
+		deptRef = me.DepartmentID.Hex()
+	}
+	if role != models.RoleSuperAdmin && deptRef == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "department is required"})
+		return
+	}
+	var dept models.Department
+	if deptRef != "" {
+		if dept, err = resolveDepartment(c.Request.Context(), deptRef, true); err != nil {
+			departmentError(c, err)
+			return
+		}
+	}
+
+	ttl := defaultInviteTTL
+	if body.ExpiresInHours > 0 {
+		ttl = time.Duration(body.ExpiresInHours) * time.Hour
+	}
+	if ttl > maxInviteTTL {
+		ttl = maxInviteTTL
+	}
+
+	raw, hash, err := newOpaqueToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "token error"})
+		return
+	}
+
+	now := time.Now().UTC()
+	inv := models.Invite{
+		TokenHash:     hash,
+		Email:         strings.ToLower(strings.TrimSpace(body.Email)),
+		Role:          role,
+		DepartmentID:  dept.ID,
+		Department:    dept.Name,
+		CreatedBy:     me.ID,
+		CreatedByName: me.Name,
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(ttl),
+	}
+	res, err := db.Col("invites").InsertOne(c.Request.Context(), inv)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	inv.ID, _ = res.InsertedID.(primitive.ObjectID)
+
+	// ham token sadece burada döner
+	c.JSON(http.StatusCreated, gin.H{
+		"invite": inviteDTO{Invite: inv, Status: inv.Status(now)},
+		"token":  raw,
+	})
+}
+
+// GET /api/invites?status=pending|used|revoked|expired[&department=...]  (admin|superadmin)
+// Admin: sadece kendi departmanının davetleri
+func ListInvites(c *gin.Context) {
+	ctx := c.Request.Context()
+	me, err := currentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		return
+	}
+
+	filter := bson.M{}
+	if me.Role == models.RoleAdmin {
+		filter["departmentId"] = me.DepartmentID
+	} else if ref := strings.TrimSpace(c.Query("department")); ref != "" {
+		d, err := resolveDepartment(ctx, ref, false)
+		if err != nil {
+			departmentError(c, err)
+			return
+		}
+		filter["departmentId"] = d.ID
+	}
+
+	now := time.Now().UTC()
+	switch strings.TrimSpace(c.Query("status")) {
+	case "pending":
+		filter["usedAt"] = bson.M{"$exists": false}
+		filter["revokedAt"] = bson.M{"$exists": false}
+		filter["expiresAt"] = bson.M{"$gt": now}
+	case "used":
+		filter["usedAt"] = bson.M{"$exists": true}
+	case "revoked":
+		filter["revokedAt"] = bson.M{"$exists": true}
+	case "expired":
+		filter["usedAt"] = bson.M{"$exists": false}
+		filter["revokedAt"] = bson.M{"$exists": false}
+		filter["expiresAt"] = bson.M{"$lte": now}
+	}
+
+	cur, err := db.Col("invites").Find(ctx, filter, optionsFindByDateDesc().SetLimit(500))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer cur.Close(ctx)
+
+	var list []models.Invite
+	if err := cur.All(ctx, &list); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	out := make([]inviteDTO, 0, len(list))
+	for _, inv := range list {
+		out = append(out, inviteDTO{Invite: inv, Status: inv.Status(now)})
+	}
+	c.JSON(http.StatusOK, gin.H{"items": out})
+}
+
+// DELETE /api/invites/:id  (admin kendi departmanı, superadmin hepsi)
+func RevokeInvite(c *gin.Context) {
+	ctx := c.Request.Context()
+	oid, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad id"})
+		return
+	}
+	me, err := currentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		return
+	}
+
+	var inv models.Invite
+	if err := db.Col("invites").FindOne(ctx, bson.M{"_id": oid}).Decode(&inv); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	if me.Role == models.RoleAdmin && inv.DepartmentID != me.DepartmentID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	if inv.UsedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "invite already used"})
+		return
+	}
+
+	now := time.Now().UTC()
+	_, err = db.Col("invites").UpdateOne(ctx,
+		bson.M{"_id": oid, "usedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revokedAt": now}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// Daveti atomik olarak "kullanıldı" işaretler. Geçersiz/kullanılmış/süresi
+// dolmuşsa mongo.ErrNoDocuments döner.
+func claimInvite(c *gin.Context, rawToken string, userID primitive.ObjectID, email string) (models.Invite, error) {
+	now := time.Now().UTC()
+	filter := bson.M{
+		"tokenHash": hashToken(rawToken),
+		"usedAt":    bson.M{"$exists": false},
+		"revokedAt": bson.M{"$exists": false},
+		"expiresAt": bson.M{"$gt": now},
+		"$or": []bson.M{
+			{"email": bson.M{"$exists": false}},
+			{"email": ""},
+			{"email": email},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"usedAt":    now,
+		"usedBy":    userID,
+		"usedEmail": email,
+	}}
+
+	var inv models.Invite
+	err := db.Col("invites").
+		FindOneAndUpdate(c.Request.Context(), filter, update,
+			options.FindOneAndUpdate().SetReturnDocument(options.After)).
+		Decode(&inv)
+	return inv, err
+}
+
+// Kullanıcı oluşturulamazsa claim'i geri al.
+func releaseInvite(c *gin.Context, id primitive.ObjectID) {
+	_, _ = db.Col("invites").UpdateByID(c.Request.Context(), id, bson.M{
+		"$unset": bson.M{"usedAt": "", "usedBy": "", "usedEmail": ""},
+	})
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Tek kullanıcıya uygulama içi bildirim yazar.
+func notify(ctx context.Context, n models.Notification) (primitive.ObjectID, error) {
+	if n.CreatedAt.IsZero() {
+		n.CreatedAt = time.Now()
+	}
+	res, err := db.Col("notifications").InsertOne(ctx, n)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	id, _ := res.InsertedID.(primitive.ObjectID)
+	return id, nil
+}
+
+// GET /api/notifications[?unread=1&limit=50]  (JWT, sadece kendi bildirimleri)
+func ListNotifications(c *gin.Context) {
+	ctx := c.Request.Context()
+	me := toOID(c.GetString("userId"))
+
+	filter := bson.M{"userId": me}
+	if c.Query("unread") == "1" {
+		filter["readAt"] = bson.M{"$exists": false}
+	}
+	limit := int64(50)
+	if v := strings.TrimSpace(c.Query("limit")); v != "" {
+		if n, e := strconv.ParseInt(v, 10, 64); e == nil && n > 0 && n <= 200 {
+			limit = n
+		}
+	}
+
+	cur, err := db.Col("notifications").Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(limit))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	items := []models.Notification{}
+	if err := cur.All(ctx, &items); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	unread, err := db.Col("notifications").CountDocuments(ctx,
+		bson.M{"userId": me, "readAt": bson.M{"$exists": false}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "unread": unread})
+}
+
+// POST /api/notifications/:id/read
+func MarkNotificationRead(c *gin.Context) {
+	oid, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad id"})
+		return
+	}
+	res, err := db.Col("notifications").UpdateOne(c.Request.Context(),
+		bson.M{"_id": oid, "userId": toOID(c.GetString("userId")), "readAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"readAt": time.Now()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "updated": res.ModifiedCount})
+}
+
+// POST /api/notifications/read-all
+func MarkAllNotificationsRead(c *gin.Context) {
+	res, err := db.Col("notifications").UpdateMany(c.Request.Context(),
+		bson.M{"userId": toOID(c.GetString("userId")), "readAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"readAt": time.Now()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "updated": res.ModifiedCount})
+}
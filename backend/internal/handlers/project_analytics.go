@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// 7d|30d|6m|12m periyodu: GetDepartmentSeries ile aynı etiketler / anahtarlar.
+type periodWindow struct {
+	Period  string
+	Monthly bool
+	Keys    []string // YYYY-MM-DD veya YYYY-MM
+	Labels  []string // "02 Jan" veya "Jan 06"
+	From    string   // YYYY-MM-DD
+	To      string   // YYYY-MM-DD (bugün)
+}
+
+func periodWindowFor(period string, now time.Time) periodWindow {
+	w := periodWindow{Period: period, To: now.Format("2006-01-02")}
+	switch period {
+	case "6m", "12m":
+		months := 6
+		if period == "12m" {
+			months = 12
+		}
+		w.Monthly = true
+		base := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		for i := months - 1; i >= 0; i-- {
+			d := base.AddDate(0, -i, 0)
+			w.Keys = append(w.Keys, d.Format("2006-01"))
+			w.Labels = append(w.Labels, d.Format("Jan 06"))
+		}
+		w.From = base.AddDate(0, -(months - 1), 0).Format("2006-01-02")
+	default:
+		days := 7
+		if period == "30d" {
+			days = 30
+		} else {
+			w.Period = "7d"
+		}
+		for i := days - 1; i >= 0; i-- {
+			d := now.AddDate(0, 0, -i)
+			w.Keys = append(w.Keys, d.Format("2006-01-02"))
+			w.Labels = append(w.Labels, d.Format("02 Jan"))
+		}
+		w.From = now.AddDate(0, 0, -(days - 1)).Format("2006-01-02")
+	}
+	return w
+}
+
+// $group anahtarı: günlük -> date, aylık -> date'in ilk 7 karakteri
+func (w periodWindow) groupKey() interface{} {
+	if w.Monthly {
+		return bson.M{"$substr": []interface{}{"$date", 0, 7}}
+	}
+	return "$date"
+}
+
+type projectKeyHours struct {
+	ProjectID primitive.ObjectID
+	Key       string
+	Hours     float64
+}
+
+// İş kalemi saatlerini proje (+ opsiyonel zaman anahtarı) bazında toplar.
+// key nil ise proje başına tek satır döner. Taslak raporlar sayılmaz.
+func aggregateProjectHours(ctx context.Context, ids []primitive.ObjectID, dateFilter bson.M, key interface{}) ([]projectKeyHours, error) {
+	match := bson.M{"entries.projectId": bson.M{"$in": ids}, "status": bson.M{"$ne": models.ReportDraft}}
+	if len(dateFilter) > 0 {
+		match["date"] = dateFilter
+	}
+	groupID := bson.M{"p": "$entries.projectId"}
+	if key != nil {
+		groupID["k"] = key
+	}
+	cur, err := db.Col("reports").Aggregate(ctx, []bson.M{
+		{"$match": match},
+		{"$unwind": "$entries"},
+		{"$match": bson.M{"entries.projectId": bson.M{"$in": ids}}},
+		{"$group": bson.M{"_id": groupID, "hours": bson.M{"$sum": "$entries.hours"}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		ID struct {
+			P primitive.ObjectID `bson:"p"`
+			K string             `bson:"k"`
+		} `bson:"_id"`
+		Hours float64 `bson:"hours"`
+	}
+	if err := cur.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	out := make([]projectKeyHours, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, projectKeyHours{ProjectID: r.ID.P, Key: r.ID.K, Hours: r.Hours})
+	}
+	return out, nil
+}
+
+// GET /api/analytics/projects?period=7d|30d|6m|12m[&department=...]  (admin|superadmin)
+// Proje başına saat serisi + tüm zamanların kullanımı ve bütçe.
+// admin: sadece kendi departmanının projeleri
+func ProjectHoursAnalytics(c *gin.Context) {
+	ctx := c.Request.Context()
+	w := periodWindowFor(strings.TrimSpace(c.Query("period")), time.Now())
+
+	filter := bson.M{}
+	if c.GetString("role") == string(models.RoleAdmin) {
+		filter["departmentId"] = myDepartmentID(c)
+	} else if ref := strings.TrimSpace(c.Query("department")); ref != "" {
+		d, err := resolveDepartment(ctx, ref, false)
+		if err != nil {
+			departmentError(c, err)
+			return
+		}
+		filter["departmentId"] = d.ID
+	}
+
+	var projects []models.Project
+	cur, err := db.Col("projects").Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := cur.All(ctx, &projects); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	type Serie struct {
+		ProjectID   string    `json:"projectId"`
+		Code        string    `json:"code"`
+		Name        string    `json:"name"`
+		Active      bool      `json:"active"`
+		Points      []float64 `json:"points"`
+		Total       float64   `json:"total"`     // periyot içi
+		UsedHours   float64   `json:"usedHours"` // tüm zamanlar
+		BudgetHours *float64  `json:"budgetHours,omitempty"`
+	}
+	out := make([]Serie, 0, len(projects))
+	if len(projects) == 0 {
+		c.JSON(http.StatusOK, gin.H{"period": w.Period, "labels": w.Labels, "dates": w.Keys, "series": out})
+		return
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(projects))
+	for _, p := range projects {
+		ids = append(ids, p.ID)
+	}
+
+	inWindow, err := aggregateProjectHours(ctx, ids, bson.M{"$gte": w.From, "$lte": w.To}, w.groupKey())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	allTime, err := aggregateProjectHours(ctx, ids, nil, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	byKey := map[primitive.ObjectID]map[string]float64{}
+	for _, r := range inWindow {
+		if byKey[r.ProjectID] == nil {
+			byKey[r.ProjectID] = map[string]float64{}
+		}
+		byKey[r.ProjectID][r.Key] += r.Hours
+	}
+	used := map[primitive.ObjectID]float64{}
+	for _, r := range allTime {
+		used[r.ProjectID] = r.Hours
+	}
+
+	for _, p := range projects {
+		points := make([]float64, len(w.Keys))
+		total := 0.0
+		for i, k := range w.Keys {
+			points[i] = byKey[p.ID][k]
+			total += points[i]
+		}
+		out = append(out, Serie{
+			ProjectID:   p.ID.Hex(),
+			Code:        p.Code,
+			Name:        p.Name,
+			Active:      p.Active,
+			Points:      points,
+			Total:       total,
+			UsedHours:   used[p.ID],
+			BudgetHours: p.BudgetHours,
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Total > out[j].Total })
+
+	c.JSON(http.StatusOK, gin.H{
+		"period": w.Period,
+		"labels": w.Labels,
+		"dates":  w.Keys,
+		"series": out,
+	})
+}
+
+// GET /api/analytics/projects/:id/burndown?period=7d|30d|6m|12m  (admin|superadmin)
+// Periyot boyunca kümülatif kullanılan saat ve (bütçe varsa) kalan saat.
+// burnRate: periyottaki günlük ortalama; projectedExhaustion: bu hızla bütçenin biteceği gün.
+func ProjectBurndown(c *gin.Context) {
+	ctx := c.Request.Context()
+	p, ok := loadManagedProject(c)
+	if !ok {
+		return
+	}
+	now := time.Now()
+	w := periodWindowFor(strings.TrimSpace(c.Query("period")), now)
+	ids := []primitive.ObjectID{p.ID}
+
+	before, err := aggregateProjectHours(ctx, ids, bson.M{"$lt": w.From}, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	rows, err := aggregateProjectHours(ctx, ids, bson.M{"$gte": w.From, "$lte": w.To}, w.groupKey())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	usedBefore := 0.0
+	for _, r := range before {
+		usedBefore += r.Hours
+	}
+	byKey := map[string]float64{}
+	windowHours := 0.0
+	for _, r := range rows {
+		byKey[r.Key] += r.Hours
+		windowHours += r.Hours
+	}
+
+	cumulative := make([]float64, len(w.Keys))
+	running := usedBefore
+	for i, k := range w.Keys {
+		running += byKey[k]
+		cumulative[i] = running
+	}
+
+	resp := gin.H{
+		"project":    p,
+		"period":     w.Period,
+		"labels":     w.Labels,
+		"dates":      w.Keys,
+		"used":       cumulative,
+		"usedBefore": usedBefore,
+		"usedHours":  running,
+	}
+
+	from, _ := time.ParseInLocation("2006-01-02", w.From, now.Location())
+	days := math.Floor(now.Sub(from).Hours()/24) + 1
+	burnRate := 0.0
+	if days > 0 {
+		burnRate = windowHours / days
+	}
+	resp["burnRate"] = burnRate
+
+	if p.BudgetHours != nil {
+		budget := *p.BudgetHours
+		remaining := make([]float64, len(cumulative))
+		for i, u := range cumulative {
+			remaining[i] = budget - u
+		}
+		left := budget - running
+		resp["budgetHours"] = budget
+		resp["remaining"] = remaining
+		resp["remainingHours"] = left
+		if burnRate > 0 && left > 0 {
+			resp["projectedExhaustion"] = now.AddDate(0, 0, int(math.Ceil(left/burnRate))).Format("2006-01-02")
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
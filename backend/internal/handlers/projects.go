@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func normalizeProjectCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// GET /api/projects[?department=...&includeInactive=1]  (JWT)
+// Herkes aktif projeleri görebilir (rapor iş kalemlerinde seçmek için);
+// pasifleri sadece admin (kendi departmanı) / superadmin listeleyebilir.
+func ListProjects(c *gin.Context) {
+	ctx := c.Request.Context()
+	role := c.GetString("role")
+
+	filter := bson.M{"active": true}
+	if ref := strings.TrimSpace(c.Query("department")); ref != "" {
+		d, err := resolveDepartment(ctx, ref, false)
+		if err != nil {
+			departmentError(c, err)
+			return
+		}
+		filter["departmentId"] = d.ID
+	}
+	if c.Query("includeInactive") == "1" && role != string(models.RoleEmployee) {
+		delete(filter, "active")
+		if role == string(models.RoleAdmin) {
+			filter["departmentId"] = myDepartmentID(c)
+		}
+	}
+
+	cur, err := db.Col("projects").Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer cur.Close(ctx)
+
+	items := []models.Project{}
+	if err := cur.All(ctx, &items); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// POST /api/projects  {name, code, department?, budgetHours?}  (admin|superadmin)
+// admin: proje her zaman kendi departmanına açılır
+func CreateProject(c *gin.Context) {
+	ctx := c.Request.Context()
+	var body struct {
+		Name        string   `json:"name"`
+		Code        string   `json:"code"`
+		Department  string   `json:"department"`
+		BudgetHours *float64 `json:"budgetHours"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.Name) == "" || strings.TrimSpace(body.Code) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name and code required"})
+		return
+	}
+	if body.BudgetHours != nil && *body.BudgetHours < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "budgetHours must be >= 0"})
+		return
+	}
+
+	deptRef := body.Department
+	if c.GetString("role") == string(models.RoleAdmin) {
+		deptRef = myDepartmentID(c).Hex()
+	}
+	dept, err := resolveDepartment(ctx, deptRef, true)
+	if err != nil {
+		departmentError(c, err)
+		return
+	}
+
+	p := models.Project{
+		Name:         strings.TrimSpace(body.Name),
+		Code:         normalizeProjectCode(body.Code),
+		DepartmentID: dept.ID,
+		Department:   dept.Name,
+		Active:       true,
+		BudgetHours:  body.BudgetHours,
+		CreatedBy:    toOID(c.GetString("userId")),
+		CreatedAt:    time.Now().UTC(),
+	}
+	res, err := db.Col("projects").InsertOne(ctx, p)
+	if mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "project code already exists"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	p.ID, _ = res.InsertedID.(primitive.ObjectID)
+	c.JSON(http.StatusCreated, p)
+}
+
+// :id projesini getirir; admin sadece kendi departmanının projelerine erişir.
+func loadManagedProject(c *gin.Context) (models.Project, bool) {
+	oid, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad id"})
+		return models.Project{}, false
+	}
+	var p models.Project
+	if err := db.Col("projects").FindOne(c.Request.Context(), bson.M{"_id": oid}).Decode(&p); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return models.Project{}, false
+	}
+	if c.GetString("role") == string(models.RoleAdmin) && p.DepartmentID != myDepartmentID(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return models.Project{}, false
+	}
+	return p, true
+}
+
+// PATCH /api/projects/:id  {name?, code?, active?, budgetHours?, clearBudget?, department?}
+// department sadece superadmin. Ad değişirse rapor iş kalemlerindeki denormalize ad da güncellenir.
+func UpdateProject(c *gin.Context) {
+	ctx := c.Request.Context()
+	var body struct {
+		Name        *string  `json:"name"`
+		Code        *string  `json:"code"`
+		Active      *bool    `json:"active"`
+		BudgetHours *float64 `json:"budgetHours"`
+		ClearBudget bool     `json:"clearBudget"`
+		Department  *string  `json:"department"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return
+	}
+	p, ok := loadManagedProject(c)
+	if !ok {
+		return
+	}
+
+	set := bson.M{"updatedAt": time.Now().UTC()}
+	update := bson.M{"$set": set}
+	if body.Name != nil {
+		if strings.TrimSpace(*body.Name) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name required"})
+			return
+		}
+		set["name"] = strings.TrimSpace(*body.Name)
+	}
+	if body.Code != nil {
+		if normalizeProjectCode(*body.Code) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "code required"})
+			return
+		}
+		set["code"] = normalizeProjectCode(*body.Code)
+	}
+	if body.Active != nil {
+		set["active"] = *body.Active
+	}
+	switch {
+	case body.ClearBudget:
+		update["$unset"] = bson.M{"budgetHours": ""}
+	case body.BudgetHours != nil:
+		if *body.BudgetHours < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "budgetHours must be >= 0"})
+			return
+		}
+		set["budgetHours"] = *body.BudgetHours
+	}
+	if body.Department != nil {
+		if c.GetString("role") != string(models.RoleSuperAdmin) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only superadmin can move projects"})
+			return
+		}
+		dept, err := resolveDepartment(ctx, *body.Department, true)
+		if err != nil {
+			departmentError(c, err)
+			return
+		}
+		set["departmentId"] = dept.ID
+		set["department"] = dept.Name
+	}
+
+	err := db.Col("projects").FindOneAndUpdate(ctx, bson.M{"_id": p.ID}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&p)
+	if mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "project code already exists"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if body.Name != nil {
+		_, _ = db.Col("reports").UpdateMany(ctx,
+			bson.M{"entries.projectId": p.ID},
+			bson.M{"$set": bson.M{"entries.$[e].project": p.Name}},
+			options.Update().SetArrayFilters(options.ArrayFilters{
+				Filters: []interface{}{bson.M{"e.projectId": p.ID}},
+			}),
+		)
+	}
+	c.JSON(http.StatusOK, p)
+}
@@ -0,0 +1,338 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ListMyReminders öğesi: hatırlatma + kullanıcının kendi durumu.
+type myReminder struct {
+	models.Reminder
+	ReadAt         *time.Time `json:"readAt,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"`
+	DismissedAt    *time.Time `json:"dismissedAt,omitempty"`
+}
+
+// ListSentReminders öğesi: hatırlatma + hedef kitledeki durum sayıları.
+type sentReminder struct {
+	models.Reminder
+	Receipts reminderReceiptCounts `json:"receipts"`
+}
+
+type reminderReceiptCounts struct {
+	Targeted     int `json:"targeted"` // şu an hedef kitlede olan aktif kullanıcılar (gönderen hariç)
+	Delivered    int `json:"delivered"`
+	Read         int `json:"read"`
+	Acknowledged int `json:"acknowledged"`
+	Dismissed    int `json:"dismissed"`
+}
+
+func (n *reminderReceiptCounts) add(r models.ReminderReceipt) {
+	if r.DeliveredAt != nil {
+		n.Delivered++
+	}
+	if r.ReadAt != nil {
+		n.Read++
+	}
+	if r.AcknowledgedAt != nil {
+		n.Acknowledged++
+	}
+	if r.DismissedAt != nil {
+		n.Dismissed++
+	}
+}
+
+// Kullanıcı hatırlatmanın hedef kitlesinde mi? (ListMyReminders varsayılan görünümüyle aynı kollar)
+func reminderTargets(rem models.Reminder, u models.User) bool {
+	if rem.TargetDepartment == "all" {
+		return true
+	}
+	if !u.DepartmentID.IsZero() {
+		if rem.TargetDepartmentID != nil && *rem.TargetDepartmentID == u.DepartmentID {
+			return true
+		}
+		if slices.Contains(rem.TargetDepartmentIDs, u.DepartmentID) {
+			return true
+		}
+	}
+	return slices.Contains(rem.TargetRoles, u.Role) || slices.Contains(rem.TargetUserIDs, u.ID)
+}
+
+// Hedef kitledeki aktif kullanıcılar (gönderen hariç). Departman üyeliği sorgu anındaki haliyle.
+func reminderRecipientFilter(rem models.Reminder) bson.M {
+	f := bson.M{"$and": []bson.M{activeUserFilter(), {"_id": bson.M{"$ne": rem.SenderID}}}}
+	if rem.TargetDepartment == "all" {
+		return f
+	}
+	depts := append([]primitive.ObjectID(nil), rem.TargetDepartmentIDs...)
+	if rem.TargetDepartmentID != nil {
+		depts = append(depts, *rem.TargetDepartmentID)
+	}
+	var or []bson.M
+	if len(depts) > 0 {
+		or = append(or, bson.M{"departmentId": bson.M{"$in": depts}})
+	}
+	if len(rem.TargetRoles) > 0 {
+		or = append(or, bson.M{"role": bson.M{"$in": rem.TargetRoles}})
+	}
+	if len(rem.TargetUserIDs) > 0 {
+		or = append(or, bson.M{"_id": bson.M{"$in": rem.TargetUserIDs}})
+	}
+	if len(or) == 0 {
+		or = []bson.M{{"_id": bson.M{"$exists": false}}} // hedefsiz: kimse
+	}
+	f["$or"] = or
+	return f
+}
+
+func loadReminderReceipts(ctx context.Context, filter bson.M) ([]models.ReminderReceipt, error) {
+	cur, err := db.Col("reminder_receipts").Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var list []models.ReminderReceipt
+	err = cur.All(ctx, &list)
+	return list, err
+}
+
+// Kullanıcının listelenen hatırlatmalardaki durumunu ekler; ilk kez gösterilenleri
+// "iletildi" olarak kaydeder. Kapatılanlar includeDismissed verilmezse çıkarılır.
+func withMyReceipts(ctx context.Context, userID primitive.ObjectID, list []models.Reminder, includeDismissed bool, now time.Time) ([]myReminder, error) {
+	out := make([]myReminder, 0, len(list))
+	if len(list) == 0 {
+		return out, nil
+	}
+	ids := make([]primitive.ObjectID, 0, len(list))
+	for _, r := range list {
+		ids = append(ids, r.ID)
+	}
+	receipts, err := loadReminderReceipts(ctx, bson.M{"userId": userID, "reminderId": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	byReminder := make(map[primitive.ObjectID]models.ReminderReceipt, len(receipts))
+	for _, r := range receipts {
+		byReminder[r.ReminderID] = r
+	}
+
+	var writes []mongo.WriteModel
+	for _, rem := range list {
+		r, ok := byReminder[rem.ID]
+		if !ok {
+			writes = append(writes, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"reminderId": rem.ID, "userId": userID}).
+				SetUpdate(bson.M{"$setOnInsert": bson.M{"deliveredAt": now}}).
+				SetUpsert(true))
+		}
+		if r.DismissedAt != nil && !includeDismissed {
+			continue
+		}
+		out = append(out, myReminder{Reminder: rem, ReadAt: r.ReadAt, AcknowledgedAt: r.AcknowledgedAt, DismissedAt: r.DismissedAt})
+	}
+	if len(writes) > 0 {
+		// eşzamanlı istekte aynı kaydı diğeri eklemiş olabilir
+		_, err := db.Col("reminder_receipts").BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// Gönderilen hatırlatmalara hedef kitle durum sayılarını ekler. Aynı hedef kitleyi
+// paylaşan hatırlatmalar için alıcılar bir kez sorgulanır.
+func withReceiptCounts(ctx context.Context, list []models.Reminder) ([]sentReminder, error) {
+	out := make([]sentReminder, 0, len(list))
+	if len(list) == 0 {
+		return out, nil
+	}
+	ids := make([]primitive.ObjectID, 0, len(list))
+	for _, r := range list {
+		ids = append(ids, r.ID)
+	}
+	receipts, err := loadReminderReceipts(ctx, bson.M{"reminderId": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	byReminder := map[primitive.ObjectID][]models.ReminderReceipt{}
+	for _, r := range receipts {
+		byReminder[r.ReminderID] = append(byReminder[r.ReminderID], r)
+	}
+
+	recipientsBy := map[string]map[primitive.ObjectID]bool{}
+	for _, rem := range list {
+		filter := reminderRecipientFilter(rem)
+		key := fmt.Sprint(filter)
+		recipients, ok := recipientsBy[key]
+		if !ok {
+			vals, err := db.Col("users").Distinct(ctx, "_id", filter)
+			if err != nil {
+				return nil, err
+			}
+			recipients = make(map[primitive.ObjectID]bool, len(vals))
+			for _, v := range vals {
+				if id, ok := v.(primitive.ObjectID); ok {
+					recipients[id] = true
+				}
+			}
+			recipientsBy[key] = recipients
+		}
+
+		row := sentReminder{Reminder: rem, Receipts: reminderReceiptCounts{Targeted: len(recipients)}}
+		for _, r := range byReminder[rem.ID] {
+			if recipients[r.UserID] {
+				row.Receipts.add(r)
+			}
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+// Durum alanlarını (ilk kez ise) şimdiye ayarlar; deliveredAt her zaman dahil.
+func markReminder(c *gin.Context, fields ...string) {
+	ctx := c.Request.Context()
+	oid, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad id"})
+		return
+	}
+	me, err := currentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		return
+	}
+	var rem models.Reminder
+	if err := db.Col("reminders").FindOne(ctx, bson.M{"_id": oid, "isActive": true}).Decode(&rem); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	// superadmin departman görünümünde hedefi olmadığı mesajları da görür
+	if me.Role != models.RoleSuperAdmin && !reminderTargets(rem, me) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	now := time.Now()
+	set := bson.M{"deliveredAt": now}
+	for _, f := range fields {
+		set[f] = now
+	}
+	var rec models.ReminderReceipt
+	for attempt := 0; attempt < 2; attempt++ {
+		// $min: alan yoksa yazar, varsa ilk zamanı korur
+		err = db.Col("reminder_receipts").FindOneAndUpdate(ctx,
+			bson.M{"reminderId": oid, "userId": me.ID},
+			bson.M{"$min": set},
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+		).Decode(&rec)
+		if !mongo.IsDuplicateKeyError(err) {
+			break // eşzamanlı upsert'te ikinci deneme mevcut kaydı günceller
+		}
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rec)
+}
+
+// POST /api/reminders/:id/read (hedef kitle)
+
+func MarkReminderRead(c *gin.Context) { markReminder(c, "readAt") }
+
+// POST /api/reminders/:id/acknowledge (hedef kitle) — okundu da sayılır
+
+func AcknowledgeReminder(c *gin.Context) { markReminder(c, "readAt", "acknowledgedAt") }
+
+// POST /api/reminders/:id/dismiss (hedef kitle) — okundu sayılır, listede gizlenir
+
+func DismissReminder(c *gin.Context) { markReminder(c, "readAt", "dismissedAt") }
+
+type reminderRecipientRow struct {
+	UserID         string     `json:"userId"`
+	Name           string     `json:"name"`
+	Department     string     `json:"department,omitempty"`
+	DeliveredAt    *time.Time `json:"deliveredAt,omitempty"`
+	ReadAt         *time.Time `json:"readAt,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"`
+}
+
+// GET /api/reminders/:id/receipts (admin kendi, superadmin herkes)
+// Durum sayıları + henüz okumayan / onaylamayan hedef kullanıcılar.
+
+func GetReminderReceipts(c *gin.Context) {
+	ctx := c.Request.Context()
+	oid, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad id"})
+		return
+	}
+	var rem models.Reminder
+	if err := db.Col("reminders").FindOne(ctx, bson.M{"_id": oid}).Decode(&rem); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("userId"))
+	if c.GetString("role") == string(models.RoleAdmin) && rem.SenderID != uid {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	ucur, err := db.Col("users").Find(ctx, reminderRecipientFilter(rem), options.Find().
+		SetProjection(bson.M{"_id": 1, "name": 1, "department": 1}).
+		SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	var users []models.User
+	if err := ucur.All(ctx, &users); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	receipts, err := loadReminderReceipts(ctx, bson.M{"reminderId": oid})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	byUser := make(map[primitive.ObjectID]models.ReminderReceipt, len(receipts))
+	for _, r := range receipts {
+		byUser[r.UserID] = r
+	}
+
+	counts := reminderReceiptCounts{Targeted: len(users)}
+	notRead, notAcked := []reminderRecipientRow{}, []reminderRecipientRow{}
+	for _, u := range users {
+		r := byUser[u.ID]
+		counts.add(r)
+		row := reminderRecipientRow{
+			UserID: u.ID.Hex(), Name: u.Name, Department: u.Department,
+			DeliveredAt: r.DeliveredAt, ReadAt: r.ReadAt, AcknowledgedAt: r.AcknowledgedAt,
+		}
+		if r.ReadAt == nil {
+			notRead = append(notRead, row)
+		}
+		if r.AcknowledgedAt == nil {
+			notAcked = append(notAcked, row)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"reminderId":      oid,
+		"receipts":        counts,
+		"notRead":         notRead,
+		"notAcknowledged": notAcked,
+	})
+}
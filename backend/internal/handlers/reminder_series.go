@@ -0,0 +1,354 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/models"
+	"report-management-system/internal/recurrence"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultReminderSeriesPoll = time.Minute
+	reminderSeriesLease       = time.Hour // çalışma çökerse bu süre sonra tekrar denenir
+	temporaryReminderTTL      = 24 * time.Hour
+)
+
+// Zamanlama alanları (CreateReminder gövdesine gömülü).
+type reminderScheduleBody struct {
+	PublishAt    string `json:"publishAt"`    // RFC3339 veya "YYYY-MM-DDTHH:MM" (sunucu saati); ileri tarihse zamanlanır
+	ExpiresAt    string `json:"expiresAt"`    // tek seferlik: duration yerine kesin bitiş
+	RRule        string `json:"rrule"`        // tekrar kuralı, örn. "FREQ=WEEKLY;BYDAY=FR;BYHOUR=16;UNTIL=20261231"
+	ExpiresAfter string `json:"expiresAfter"` // tekrarlı: her tekrarın süresi ("8h", "72h")
+}
+
+type reminderSchedule struct {
+	start        time.Time // yayın zamanı / tekrar başlangıcı
+	deferred     bool      // hemen yayınlanmaz, reminder_series'e yazılır
+	rrule        string
+	first        time.Time // ilk yayın (deferred)
+	duration     string
+	expiresAt    *time.Time    // tek seferlik
+	expiresAfter time.Duration // tekrarlı; 0 = kalıcı
+}
+
+var reminderTimeLayouts = []string{"2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02T15:04:05"}
+
+// RFC3339 (saat dilimli) ya da yerel "YYYY-MM-DDTHH:MM"; boşsa nil.
+func parseReminderTime(v string) (*time.Time, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, nil
+	}
+	for _, layout := range reminderTimeLayouts {
+		if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, errors.New("must be RFC3339 or YYYY-MM-DDTHH:MM")
+}
+
+// Gövdedeki zamanlamayı doğrular; hata yanıtını kendisi yazar.
+func (b reminderScheduleBody) parse(c *gin.Context, dur string, now time.Time) (reminderSchedule, bool) {
+	s := reminderSchedule{start: now, duration: dur}
+	publishAt, err := parseReminderTime(b.PublishAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "publishAt " + err.Error()})
+		return s, false
+	}
+	if publishAt != nil && publishAt.After(now) {
+		s.start, s.deferred = *publishAt, true
+	}
+	expiresAt, err := parseReminderTime(b.ExpiresAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expiresAt " + err.Error()})
+		return s, false
+	}
+
+	s.rrule = strings.TrimSpace(b.RRule)
+	if s.rrule == "" {
+		if strings.TrimSpace(b.ExpiresAfter) != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expiresAfter is only used with rrule"})
+			return s, false
+		}
+		switch {
+		case expiresAt != nil:
+			if !expiresAt.After(s.start) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "expiresAt must be after the publish time"})
+				return s, false
+			}
+			s.expiresAt, s.duration = expiresAt, "custom"
+		case dur == "temporary":
+			t := s.start.Add(temporaryReminderTTL)
+			s.expiresAt = &t
+		}
+		s.first = s.start
+		return s, true
+	}
+
+	// tekrarlı
+	if expiresAt != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "use expiresAfter (per occurrence) with rrule"})
+		return s, false
+	}
+	rule, err := recurrence.Parse(s.rrule, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return s, false
+	}
+	first, ok := rule.First(s.start)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "rrule has no upcoming occurrence"})
+		return s, false
+	}
+	s.deferred, s.first = true, first
+	switch v := strings.TrimSpace(b.ExpiresAfter); {
+	case v != "":
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expiresAfter must be a positive duration like 8h"})
+			return s, false
+		}
+		s.expiresAfter, s.duration = d, "custom"
+	case dur == "temporary":
+		s.expiresAfter = temporaryReminderTTL
+	}
+	return s, true
+}
+
+// Zamanlanmış/tekrarlı hatırlatmayı kaydeder; yayını zamanlayıcı yapar.
+func createReminderSeries(c *gin.Context, rem models.Reminder, s reminderSchedule, now time.Time) {
+	series := models.ReminderSeries{
+		Template:  rem,
+		RRule:     s.rrule,
+		StartAt:   s.start,
+		ExpiresAt: s.expiresAt,
+		Status:    models.ReminderSeriesScheduled,
+		NextRunAt: &s.first,
+		CreatedAt: now,
+	}
+	if s.expiresAfter > 0 {
+		series.ExpiresAfter = s.expiresAfter.String()
+	}
+	res, err := db.Col("reminder_series").InsertOne(c.Request.Context(), series)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"id": res.InsertedID, "scheduled": true, "nextRunAt": s.first})
+}
+
+// ---------- zamanlayıcı ----------
+
+// Tekrarı düz bir hatırlatma olarak yazar. (seriesId, occurrenceAt) tekil olduğu için
+// yeniden denemede ikinci kopya oluşmaz; mevcut kaydın ID'si döner.
+func publishReminderOccurrence(ctx context.Context, s models.ReminderSeries, occ time.Time, expires *time.Time, now time.Time) (primitive.ObjectID, error) {
+	rem := s.Template
+	rem.ID = primitive.NewObjectID()
+	rem.SeriesID, rem.OccurrenceAt = &s.ID, &occ
+	rem.IsActive, rem.ExpiresAt, rem.CreatedAt = true, expires, now
+	_, err := db.Col("reminders").InsertOne(ctx, rem)
+	if mongo.IsDuplicateKeyError(err) {
+		var existing models.Reminder
+		err = db.Col("reminders").FindOne(ctx, bson.M{"seriesId": s.ID, "occurrenceAt": occ}).Decode(&existing)
+		return existing.ID, err
+	}
+	return rem.ID, err
+}
+
+// Sahiplenilen serinin sıradaki tekrarını yayınlar ve bir sonrakini planlar.
+// Sunucu kapalıyken kaçırılan tekrarlardan sadece en sonuncusu (süresi dolmadıysa) yayınlanır.
+func runReminderSeries(ctx context.Context, s models.ReminderSeries, now time.Time) error {
+	occ := *s.NextRunAt
+	occurrences := s.Occurrences + 1
+
+	var next *time.Time
+	var expires *time.Time
+	if s.RRule == "" {
+		expires = s.ExpiresAt
+	} else {
+		rule, err := recurrence.Parse(s.RRule, time.Local)
+		if err != nil {
+			return err
+		}
+		if rule.Count == 0 || occurrences < rule.Count {
+			if t, ok := rule.Next(s.StartAt, occ); ok {
+				next = &t
+			}
+		}
+		if s.ExpiresAfter != "" {
+			if d, err := time.ParseDuration(s.ExpiresAfter); err == nil {
+				t := occ.Add(d)
+				expires = &t
+			}
+		}
+	}
+
+	set := bson.M{"occurrences": occurrences, "lastRunAt": now, "lastError": ""}
+	superseded := next != nil && !next.After(now)
+	expired := expires != nil && !expires.After(now)
+	if !superseded && !expired {
+		id, err := publishReminderOccurrence(ctx, s, occ, expires, now)
+		if err != nil {
+			return err
+		}
+		set["lastReminderId"] = id
+		set["published"] = s.Published + 1
+	}
+
+	unset := bson.M{"leaseUntil": ""}
+	update := bson.M{"$set": set, "$unset": unset}
+	if next != nil {
+		set["nextRunAt"] = *next
+	} else {
+		set["status"] = models.ReminderSeriesCompleted
+		unset["nextRunAt"] = ""
+	}
+	// bu arada iptal edildiyse dokunma
+	_, err := db.Col("reminder_series").UpdateOne(ctx,
+		bson.M{"_id": s.ID, "status": models.ReminderSeriesScheduled}, update)
+	return err
+}
+
+// Zamanı gelen serileri tek tek sahiplenip çalıştırır. Sahiplenme leaseUntil ile
+// yapılır (birden fazla sunucu örneği aynı tekrarı iki kez işlemez); nextRunAt
+// gerçek tekrar zamanı olarak kalır. Çalışma çökerse ya da hata verirse seri
+// kira bitince aynı tekrarla yeniden denenir ve uniq_series_occurrence sayesinde
+// ikinci bir hatırlatma yazılmaz.
+func runDueReminderSeries(ctx context.Context, now time.Time) {
+	col := db.Col("reminder_series")
+	for {
+		var s models.ReminderSeries
+		err := col.FindOneAndUpdate(ctx,
+			bson.M{
+				"status":    models.ReminderSeriesScheduled,
+				"nextRunAt": bson.M{"$lte": now},
+				"$or": bson.A{
+					bson.M{"leaseUntil": bson.M{"$exists": false}},
+					bson.M{"leaseUntil": bson.M{"$lte": now}},
+				},
+			},
+			bson.M{"$set": bson.M{"leaseUntil": now.Add(reminderSeriesLease)}},
+			options.FindOneAndUpdate().SetSort(bson.M{"nextRunAt": 1}),
+		).Decode(&s)
+		if err == mongo.ErrNoDocuments {
+			return
+		}
+		if err != nil {
+			log.Println("reminder series:", err)
+			return
+		}
+		if err := runReminderSeries(ctx, s, now); err != nil {
+			log.Printf("reminder series %s: %v", s.ID.Hex(), err)
+			_, _ = col.UpdateByID(ctx, s.ID, bson.M{"$set": bson.M{"lastError": err.Error()}})
+		}
+	}
+}
+
+// StartReminderSeries: zamanlanmış/tekrarlı hatırlatmaları REMINDER_SCHEDULE_POLL
+// aralığıyla (varsayılan 1m) yayınlar; ctx iptal edilince durur.
+func StartReminderSeries(ctx context.Context) {
+	t := time.NewTicker(envDuration("REMINDER_SCHEDULE_POLL", defaultReminderSeriesPoll))
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-t.C:
+			runDueReminderSeries(ctx, now)
+		}
+	}
+}
+
+// ---------- yönetim ----------
+
+// GET /api/reminders/scheduled (admin kendi, superadmin herkes)
+// - includeInactive=1 verilmezse sadece bekleyen (scheduled) seriler
+
+func ListScheduledReminders(c *gin.Context) {
+	filter := bson.M{}
+	if c.GetString("role") == string(models.RoleAdmin) {
+		uid, _ := primitive.ObjectIDFromHex(c.GetString("userId"))
+		filter["template.senderId"] = uid
+	}
+	if c.Query("includeInactive") != "1" {
+		filter["status"] = models.ReminderSeriesScheduled
+	}
+
+	cur, err := db.Col("reminder_series").Find(c.Request.Context(), filter, optionsFindByDateDesc())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	list := []models.ReminderSeries{}
+	if err := cur.All(c.Request.Context(), &list); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": list})
+}
+
+// DELETE /api/reminders/scheduled/:id?deactivate=1 (admin kendi, superadmin herkes)
+// Bekleyen tekrarları iptal eder; deactivate=1 ise yayınlanmış olanları da kaldırır.
+
+func CancelScheduledReminder(c *gin.Context) {
+	ctx := c.Request.Context()
+	oid, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad id"})
+		return
+	}
+	var s models.ReminderSeries
+	if err := db.Col("reminder_series").FindOne(ctx, bson.M{"_id": oid}).Decode(&s); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("userId"))
+	if c.GetString("role") == string(models.RoleAdmin) && s.Template.SenderID != uid {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	now := time.Now()
+	res, err := db.Col("reminder_series").UpdateOne(ctx,
+		bson.M{"_id": oid, "status": models.ReminderSeriesScheduled},
+		bson.M{
+			"$set":   bson.M{"status": models.ReminderSeriesCancelled, "cancelledAt": now},
+			"$unset": bson.M{"nextRunAt": "", "leaseUntil": ""},
+		})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if res.MatchedCount == 0 && c.Query("deactivate") != "1" {
+		c.JSON(http.StatusConflict, gin.H{"error": "reminder is not scheduled"})
+		return
+	}
+
+	var deactivated int64
+	if c.Query("deactivate") == "1" {
+		ur, err := db.Col("reminders").UpdateMany(ctx,
+			bson.M{"seriesId": oid, "isActive": true},
+			bson.M{"$set": bson.M{"isActive": false}})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		deactivated = ur.ModifiedCount
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "deactivated": deactivated})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 	"time"
@@ -14,7 +15,151 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+const (
+	maxReminderDepartments = 50
+	maxReminderUsers       = 500
+)
+
+// Hedef kitle alanları (birleşim): departman, rol, kullanıcı listeleri.
+type reminderAudienceBody struct {
+	TargetDepartment string   `json:"targetDepartment"` // "all" | "<dept adı veya ID>" (eski tek hedef)
+	Departments      []string `json:"departments"`      // ad veya ID
+	Roles            []string `json:"roles"`            // employee|admin|superadmin
+	UserIDs          []string `json:"userIds"`
+}
+
+var errReminderAudience = errors.New("admins can only target their own department or its users")
+
+// Hedef kitleyi doğrulayıp hatırlatmaya yazar.
+// - admin: kendi departmanı (varsayılan) ve/veya departmanındaki kullanıcılar; rol hedefi yok
+// - superadmin: hiçbir hedef yoksa veya targetDepartment "all" ise herkes
+func applyReminderAudience(c *gin.Context, sender models.User, body reminderAudienceBody, rem *models.Reminder) bool {
+	ctx := c.Request.Context()
+	isAdmin := sender.Role == models.RoleAdmin
+
+	refs := append([]string(nil), body.Departments...)
+	target := strings.TrimSpace(body.TargetDepartment)
+	if isAdmin {
+		target = "" // eski tek hedef alanı admin için her zaman kendi departmanıydı
+	}
+	all := strings.EqualFold(target, "all")
+	if target != "" && !all {
+		refs = append(refs, target)
+	}
+	if len(refs) > maxReminderDepartments || len(body.UserIDs) > maxReminderUsers {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "too many targets"})
+		return false
+	}
+
+	// departmanlar
+	seenDept := map[primitive.ObjectID]bool{}
+	for _, ref := range refs {
+		if strings.TrimSpace(ref) == "" {
+			continue
+		}
+		d, err := resolveDepartment(ctx, ref, true)
+		if err != nil {
+			departmentError(c, err)
+			return false
+		}
+		if isAdmin && d.ID != sender.DepartmentID {
+			c.JSON(http.StatusForbidden, gin.H{"error": errReminderAudience.Error()})
+			return false
+		}
+		if !seenDept[d.ID] {
+			seenDept[d.ID] = true
+			rem.TargetDepartmentIDs = append(rem.TargetDepartmentIDs, d.ID)
+			rem.TargetDepartments = append(rem.TargetDepartments, d.Name)
+		}
+	}
+
+	// roller
+	seenRole := map[models.Role]bool{}
+	for _, r := range body.Roles {
+		role := models.Role(strings.ToLower(strings.TrimSpace(r)))
+		switch role {
+		case "":
+			continue
+		case models.RoleEmployee, models.RoleAdmin, models.RoleSuperAdmin:
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "roles must be employee, admin or superadmin"})
+			return false
+		}
+		if isAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": errReminderAudience.Error()})
+			return false
+		}
+		if !seenRole[role] {
+			seenRole[role] = true
+			rem.TargetRoles = append(rem.TargetRoles, role)
+		}
+	}
+
+	// kullanıcılar (aktif olmalı; admin için kendi departmanından)
+	if len(body.UserIDs) > 0 {
+		ids := make([]primitive.ObjectID, 0, len(body.UserIDs))
+		seenUser := map[primitive.ObjectID]bool{}
+		for _, v := range body.UserIDs {
+			oid, err := primitive.ObjectIDFromHex(strings.TrimSpace(v))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "bad user id: " + v})
+				return false
+			}
+			if !seenUser[oid] {
+				seenUser[oid] = true
+				ids = append(ids, oid)
+			}
+		}
+		filter := bson.M{"$and": []bson.M{{"_id": bson.M{"$in": ids}}, activeUserFilter()}}
+		if isAdmin {
+			filter["departmentId"] = sender.DepartmentID
+		}
+		n, err := db.Col("users").CountDocuments(ctx, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return false
+		}
+		if int(n) != len(ids) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown or inactive user in userIds"})
+			return false
+		}
+		rem.TargetUserIDs = ids
+	}
+
+	empty := len(rem.TargetDepartmentIDs) == 0 && len(rem.TargetRoles) == 0 && len(rem.TargetUserIDs) == 0
+	switch {
+	case empty && isAdmin:
+		// admin varsayılanı: kendi departmanı
+		if sender.DepartmentID.IsZero() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "admin has no department"})
+			return false
+		}
+		d, err := resolveDepartment(ctx, sender.DepartmentID.Hex(), true)
+		if err != nil {
+			departmentError(c, err)
+			return false
+		}
+		rem.TargetDepartmentIDs, rem.TargetDepartments = []primitive.ObjectID{d.ID}, []string{d.Name}
+	case empty || all:
+		rem.TargetDepartment = "all"
+		rem.TargetDepartmentIDs, rem.TargetDepartments, rem.TargetRoles, rem.TargetUserIDs = nil, nil, nil, nil
+		return true
+	}
+
+	// tek departmanlık hedef: eski alanlar da dolu (eski istemciler bunu gösterir)
+	if len(rem.TargetDepartmentIDs) == 1 && len(rem.TargetRoles) == 0 && len(rem.TargetUserIDs) == 0 {
+		id := rem.TargetDepartmentIDs[0]
+		rem.TargetDepartment, rem.TargetDepartmentID = rem.TargetDepartments[0], &id
+	}
+	return true
+}
+
 // POST /api/reminders (admin/superadmin)
+// {content, type, duration, targetDepartment?, departments?, roles?, userIds?,
+//  publishAt?, expiresAt?, rrule?, expiresAfter?}
+// Hedef kitle birleşimdir: listelenen departmanlardan, rollerden veya kullanıcılardan
+// herhangi birine uyan herkes görür. İleri tarihli publishAt ya da rrule verilirse
+// hatırlatma reminder_series'e kaydedilir ve zamanı gelince yayınlanır.
 
 func CreateReminder(c *gin.Context) {
 	role := c.GetString("role")
@@ -24,10 +169,11 @@ func CreateReminder(c *gin.Context) {
 	}
 
 	var body struct {
-		Content          string `json:"content"`
-		Type             string `json:"type"`             // info|warning|success|error
-		TargetDepartment string `json:"targetDepartment"` // "all" | "<dept>"
-		Duration         string `json:"duration"`         // temporary|permanent
+		Content  string `json:"content"`
+		Type     string `json:"type"`     // info|warning|success|error
+		Duration string `json:"duration"` // temporary|permanent (expiresAt/expiresAfter verilirse custom)
+		reminderAudienceBody
+		reminderScheduleBody
 	}
 	if err := c.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.Content) == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
@@ -57,43 +203,31 @@ func CreateReminder(c *gin.Context) {
 		dur = "temporary"
 	}
 
-	// hedef departman kuralı:
-	// - admin: sadece kendi departmanı
-	// - superadmin: boşsa "all", doluysa olduğu gibi
-	target := strings.TrimSpace(body.TargetDepartment)
-	if role == string(models.RoleAdmin) {
-		target = strings.TrimSpace(sender.Department)
-		if target == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "admin has no department"})
-			return
-		}
-	} else {
-		// superadmin
-		if target == "" {
-			target = "all"
-		}
-	}
-
-	// süreye göre expiresAt
+	// yayın zamanı ve süreye göre expiresAt
 	now := time.Now()
-	var expires *time.Time
-	if dur == "temporary" {
-		t := now.Add(24 * time.Hour)
-		expires = &t
+	sched, ok := body.reminderScheduleBody.parse(c, dur, now)
+	if !ok {
+		return
 	}
 
 	rem := models.Reminder{
-		Content:          strings.TrimSpace(body.Content),
-		Type:             models.ReminderType(typ),
-		TargetDepartment: target,
-		SenderID:         sender.ID,
-		SenderName:       sender.Name,
-		SenderRole:       sender.Role,
-		Duration:         dur,
-		IsActive:         true,
-		ExpiresAt:        expires,
-		CreatedAt:        now,
+		Content:    strings.TrimSpace(body.Content),
+		Type:       models.ReminderType(typ),
+		SenderID:   sender.ID,
+		SenderName: sender.Name,
+		SenderRole: sender.Role,
+		Duration:   sched.duration,
+		IsActive:   true,
+		CreatedAt:  now,
+	}
+	if !applyReminderAudience(c, sender, body.reminderAudienceBody, &rem) {
+		return
+	}
+	if sched.deferred {
+		createReminderSeries(c, rem, sched, now)
+		return
 	}
+	rem.ExpiresAt = sched.expiresAt
 
 	res, err := db.Col("reminders").InsertOne(c.Request.Context(), rem)
 	if err != nil {
@@ -103,10 +237,29 @@ func CreateReminder(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"id": res.InsertedID})
 }
 
+// Aktif + süresi dolmamış hatırlatmalar, hedef kitle kolları üzerinden.
+// Her $or kolu {isActive, <hedef alan>, expiresAt} + createdAt sıralıdır; yani
+// EnsureReminderIndexes'teki active_*_exp_created indexlerinden biriyle eşleşir.
+func reminderAudienceQuery(now time.Time, targets ...bson.M) bson.M {
+	or := make([]bson.M, 0, len(targets))
+	for _, t := range targets {
+		branch := bson.M{
+			"isActive":  true,
+			"expiresAt": bson.M{"$not": bson.M{"$lte": now}}, // yok ya da ileride
+		}
+		for k, v := range t {
+			branch[k] = v
+		}
+		or = append(or, branch)
+	}
+	return bson.M{"$or": or}
+}
+
 // GET /api/reminders (JWT)
-// - Çalışan/Admin: all + kendi departmanı (aktif, zamanı geçmemiş)
+// - Çalışan/Admin: all + kendi departmanı + rolü + kişisel hatırlatmalar (aktif, zamanı geçmemiş)
 // - Superadmin & ?department=Sales: Sales'a (ve "all"a) gönderilen aktif mesajlar
-// - Superadmin & paramsız: all + kendi departmanı
+// - Superadmin & paramsız: all + kendi departmanı + rolü + kişisel
+// Her öğede kullanıcının readAt/acknowledgedAt'i; kapatılanlar includeDismissed=1 verilmezse gizli.
 
 func ListMyReminders(c *gin.Context) {
 	uidHex := c.GetString("userId")
@@ -119,53 +272,35 @@ func ListMyReminders(c *gin.Context) {
 	}
 
 	now := time.Now()
-	expiresOk := bson.M{"$or": []bson.M{
-		{"expiresAt": bson.M{"$gt": now}},
-		{"expiresAt": bson.M{"$exists": false}},
-	}}
+	targets := []bson.M{{"targetDepartment": "all"}}
 
 	// Superadmin belirli bir departmanı görmek isterse (?department=Sales)
 	dept := strings.TrimSpace(c.Query("department"))
 	if me.Role == models.RoleSuperAdmin && dept != "" {
-		query := bson.M{
-			"$and": []bson.M{
-				{"isActive": true},
-				expiresOk,
-				{"$or": []bson.M{
-					{"targetDepartment": "all"},
-					{"targetDepartment": dept},
-				}},
-			},
-		}
-		cur, err := db.Col("reminders").Find(c.Request.Context(), query, optionsFindByDateDesc())
+		d, err := resolveDepartment(c.Request.Context(), dept, false)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			departmentError(c, err)
 			return
 		}
-		defer cur.Close(c.Request.Context())
-
-		var list []models.Reminder
-		if err := cur.All(c.Request.Context(), &list); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
+		targets = append(targets,
+			bson.M{"targetDepartmentId": d.ID}, // eski kayıtlar
+			bson.M{"targetDepartmentIds": d.ID},
+		)
+	} else {
+		// Varsayılan görünüm
+		if !me.DepartmentID.IsZero() {
+			targets = append(targets,
+				bson.M{"targetDepartmentId": me.DepartmentID}, // eski kayıtlar
+				bson.M{"targetDepartmentIds": me.DepartmentID},
+			)
 		}
-		c.JSON(http.StatusOK, gin.H{"items": list})
-		return
+		targets = append(targets,
+			bson.M{"targetRoles": me.Role},
+			bson.M{"targetUserIds": me.ID},
+		)
 	}
 
-	// Varsayılan görünüm
-	query := bson.M{
-		"$and": []bson.M{
-			{"isActive": true},
-			expiresOk,
-			{"$or": []bson.M{
-				{"targetDepartment": "all"},
-				{"targetDepartment": me.Department},
-			}},
-		},
-	}
-
-	cur, err := db.Col("reminders").Find(c.Request.Context(), query, optionsFindByDateDesc())
+	cur, err := db.Col("reminders").Find(c.Request.Context(), reminderAudienceQuery(now, targets...), optionsFindByDateDesc())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -177,11 +312,17 @@ func ListMyReminders(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"items": list})
+	items, err := withMyReceipts(c.Request.Context(), me.ID, list, c.Query("includeDismissed") == "1", now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
 }
 
 // GET /api/reminders/sent (admin/superadmin)
 //- includeInactive=1 verilmezse aktif + süresi geçmemiş
+//- her öğede receipts: {targeted, delivered, read, acknowledged, dismissed}
 
 func ListSentReminders(c *gin.Context) {
 	role := c.GetString("role")
@@ -222,7 +363,12 @@ func ListSentReminders(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"items": list})
+	items, err := withReceiptCounts(c.Request.Context(), list)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
 }
 
 // DELETE /api/reminders/:id (admin kendi, superadmin herkes)
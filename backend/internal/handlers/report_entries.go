@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const uncategorized = "uncategorized"
+
+var errEntryHours = errors.New("total hours must be between 0 and 24")
+
+// İş kalemlerini temizler/doğrular ve toplam saati döner.
+// Toplam, clampHours'un 0–24 aralığında olmalı (kırpılmaz, reddedilir).
+func normalizeEntries(in []models.ReportEntry) ([]models.ReportEntry, float64, error) {
+	out := make([]models.ReportEntry, 0, len(in))
+	total := 0.0
+	for i, e := range in {
+		e.Description = strings.TrimSpace(e.Description)
+		e.Category = strings.TrimSpace(e.Category)
+		e.Project = strings.TrimSpace(e.Project)
+		if e.Description == "" {
+			return nil, 0, fmt.Errorf("entry %d: description required", i+1)
+		}
+		if e.Hours < 0 || e.Hours > 24 {
+			return nil, 0, fmt.Errorf("entry %d: hours must be between 0 and 24", i+1)
+		}
+		switch e.Status {
+		case "":
+			e.Status = models.EntryDone
+		case models.EntryDone, models.EntryInProgress, models.EntryBlocked:
+		default:
+			return nil, 0, fmt.Errorf("entry %d: invalid status %q", i+1, e.Status)
+		}
+		total += e.Hours
+		out = append(out, e)
+	}
+	if total != clampHours(total) {
+		return nil, 0, errEntryHours
+	}
+	return out, total, nil
+}
+
+// content verilmediğinde iş kalemlerinden düz metin özet üretir.
+func entriesSummary(entries []models.ReportEntry) string {
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		line := "- " + e.Description
+		if e.Category != "" {
+			line += " [" + e.Category + "]"
+		}
+		line += fmt.Sprintf(" (%gh", e.Hours)
+		if e.Status != models.EntryDone {
+			line += ", " + string(e.Status)
+		}
+		lines = append(lines, line+")")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func entriesEqual(a, b []models.ReportEntry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		x, y := a[i], b[i]
+		if x.ProjectID != nil && y.ProjectID != nil && *x.ProjectID == *y.ProjectID {
+			x.ProjectID, y.ProjectID = nil, nil
+		}
+		if x != y {
+			return false
+		}
+	}
+	return true
+}
+
+// İş kalemlerindeki proje referanslarını çözer. projectId verilmişse proje aktif
+// olmalı ve ad denormalize edilir; sadece project metni verilmişse aktif bir projenin
+// koduyla eşleşirse bağlanır, eşleşmezse serbest metin olarak kalır.
+func resolveEntryProjects(ctx context.Context, entries []models.ReportEntry) error {
+	byID := map[primitive.ObjectID]models.Project{}
+	for i := range entries {
+		e := &entries[i]
+		var p models.Project
+		var err error
+		switch {
+		case e.ProjectID != nil:
+			if cached, ok := byID[*e.ProjectID]; ok {
+				p = cached
+				break
+			}
+			err = db.Col("projects").FindOne(ctx, bson.M{"_id": *e.ProjectID}).Decode(&p)
+		case e.Project != "":
+			err = db.Col("projects").FindOne(ctx, bson.M{"code": strings.ToUpper(e.Project), "active": true}).Decode(&p)
+			if err == mongo.ErrNoDocuments {
+				continue
+			}
+		default:
+			continue
+		}
+		if err == mongo.ErrNoDocuments {
+			return fmt.Errorf("entry %d: unknown project", i+1)
+		}
+		if err != nil {
+			return err
+		}
+		if !p.Active {
+			return fmt.Errorf("entry %d: project %s is inactive", i+1, p.Code)
+		}
+		byID[p.ID] = p
+		id := p.ID
+		e.ProjectID = &id
+		e.Project = p.Name
+	}
+	return nil
+}
+
+// Raporun saatlerini kategoriye göre dağıtır; iş kalemi olmayan (eski) raporlar
+// ve kategorisiz kalemler "uncategorized" altında toplanır.
+func reportCategoryHours(r models.Report) map[string]float64 {
+	out := map[string]float64{}
+	if len(r.Entries) == 0 {
+		out[uncategorized] = clampHours(r.Hours)
+		return out
+	}
+	for _, e := range r.Entries {
+		cat := e.Category
+		if cat == "" {
+			cat = uncategorized
+		}
+		out[cat] += e.Hours
+	}
+	return out
+}
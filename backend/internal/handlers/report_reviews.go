@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GET /api/reports/review-queue?status=submitted|changes_requested|approved|draft&department=&limit=&skip=
+// (admin|superadmin) — varsayılan: onay bekleyen (submitted) raporlar, en eskisi önce.
+// admin: sadece kendi departmanı (kendi raporu hariç)
+func GetReviewQueue(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	status := models.ReportStatus(strings.TrimSpace(c.Query("status")))
+	switch status {
+	case "":
+		status = models.ReportSubmitted
+	case models.ReportSubmitted, models.ReportChangesRequested, models.ReportApproved, models.ReportDraft:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+		return
+	}
+
+	limit := int64(50)
+	skip := int64(0)
+	if v := strings.TrimSpace(c.Query("limit")); v != "" {
+		if n, e := strconv.ParseInt(v, 10, 64); e == nil && n > 0 && n <= 200 {
+			limit = n
+		}
+	}
+	if v := strings.TrimSpace(c.Query("skip")); v != "" {
+		if n, e := strconv.ParseInt(v, 10, 64); e == nil && n >= 0 {
+			skip = n
+		}
+	}
+
+	userFilter := bson.M{}
+	if c.GetString("role") == string(models.RoleAdmin) {
+		userFilter["departmentId"] = myDepartmentID(c)
+		userFilter["_id"] = bson.M{"$ne": toOID(c.GetString("userId"))}
+	} else if ref := strings.TrimSpace(c.Query("department")); ref != "" {
+		d, err := resolveDepartment(ctx, ref, false)
+		if err != nil {
+			departmentError(c, err)
+			return
+		}
+		userFilter["departmentId"] = d.ID
+	}
+
+	filter := bson.M{"status": status}
+	if status == models.ReportSubmitted {
+		// iş akışı öncesi (status alanı olmayan) raporlar da incelemeye açık
+		filter["status"] = bson.M{"$in": []interface{}{models.ReportSubmitted, nil}}
+	}
+	if len(userFilter) > 0 {
+		ucur, err := db.Col("users").Find(ctx, userFilter, options.Find().SetProjection(bson.M{"_id": 1}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		var users []models.User
+		if err := ucur.All(ctx, &users); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ids := make([]primitive.ObjectID, 0, len(users))
+		for _, u := range users {
+			ids = append(ids, u.ID)
+		}
+		filter["userId"] = bson.M{"$in": ids}
+	}
+
+	total, err := db.Col("reports").CountDocuments(ctx, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	cur, err := db.Col("reports").Find(ctx, filter,
+		options.Find().
+			SetSort(bson.D{{Key: "date", Value: 1}, {Key: "submittedAt", Value: 1}}).
+			SetLimit(limit).
+			SetSkip(skip),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer cur.Close(ctx)
+
+	items := []models.Report{}
+	if err := cur.All(ctx, &items); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total, "status": status})
+}
+
+// POST /api/reports/:id/approve  {comment}  (admin|superadmin)
+func ApproveReport(c *gin.Context) {
+	reviewReport(c, models.ReportApproved)
+}
+
+// POST /api/reports/:id/reject  {comment}  (admin|superadmin) — düzeltme ister
+func RejectReport(c *gin.Context) {
+	reviewReport(c, models.ReportChangesRequested)
+}
+
+// Sadece submitted (veya onay akışı öncesi, status'suz) raporlar incelenebilir.
+// Kimse kendi raporunu inceleyemez; admin sadece kendi departmanındaki employee'leri.
+func reviewReport(c *gin.Context, status models.ReportStatus) {
+	ctx := c.Request.Context()
+	var body struct {
+		Comment string `json:"comment"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.Comment) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "comment required"})
+		return
+	}
+
+	rep, ok := loadAccessibleReport(c)
+	if !ok {
+		return
+	}
+	if rep.UserID.Hex() == c.GetString("userId") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "cannot review your own report"})
+		return
+	}
+	var owner models.User
+	if err := db.Col("users").FindOne(ctx, bson.M{"_id": rep.UserID}).Decode(&owner); err == nil && !canModifyUser(c, owner) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	if rep.EffectiveStatus() != models.ReportSubmitted {
+		c.JSON(http.StatusConflict, gin.H{"error": "report is " + string(rep.Status)})
+		return
+	}
+
+	me, err := currentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		return
+	}
+
+	// status şartı: inceleme sırasında rapor değiştiyse (ör. taslağa çekildi) çakışma
+	err = db.Col("reports").FindOneAndUpdate(ctx,
+		bson.M{"_id": rep.ID, "status": bson.M{"$in": []interface{}{models.ReportSubmitted, nil}}},
+		bson.M{
+			"$set": bson.M{
+				"status":         status,
+				"reviewedBy":     me.ID,
+				"reviewedByName": me.Name,
+				"reviewedAt":     time.Now(),
+				"reviewComment":  strings.TrimSpace(body.Comment),
+			},
+			// incelenen rapor artık CSV import geri alımına dahil değil
+			"$unset": bson.M{"importBatchId": ""},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&rep)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusConflict, gin.H{"error": "report changed, reload and try again"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rep)
+}
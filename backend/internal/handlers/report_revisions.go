@@ -0,0 +1,440 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Bir rapor yazımı: yeni değerler + kimin, hangi sebeple (update/restore) yaptığı.
+// Entries varsa Hours onların toplamıdır (reportBody.edit).
+type reportEdit struct {
+	Content      string
+	Hours        float64
+	Entries      []models.ReportEntry
+	Editor       models.User
+	Action       models.RevisionAction // boşsa create/update otomatik
+	RestoredFrom int
+
+	// Status boşsa mevcut durum korunur (yeni kayıt: submitted); sadece onaylanmış
+	// raporun içeriği değişirse onay düşer ve rapor tekrar submitted olur.
+	// OwnerEdit: rapor sahibi yazıyor; onaylanmış rapor sahibine salt okunurdur.
+	Status    models.ReportStatus
+	OwnerEdit bool
+}
+
+var (
+	errReportApproved = errors.New("report is approved and read-only")
+	errReportConflict = errors.New("report was modified concurrently; please retry")
+)
+
+const (
+	// Eşzamanlı yazımda saveReport'un yeniden deneme sayısı.
+	maxReportWriteAttempts = 3
+	// Raporu hiç yazılmamış revizyonun yarım kalmış sayılması için geçmesi gereken süre.
+	danglingRevisionAge = time.Minute
+)
+
+// status alanları; submitted'a geçişte submittedAt damgalanır.
+func reportStatusFields(st models.ReportStatus, now time.Time) bson.M {
+	out := bson.M{"status": st}
+	if st == models.ReportSubmitted {
+		out["submittedAt"] = now
+	}
+	return out
+}
+
+// (owner, date) raporunu yazar ve değişikliği report_revisions'a ekler.
+// Transaction kullanılmaz (standalone MongoDB'de de çalışır): önce yeni rev'in
+// revizyonu eklenir, sonra rapor okunan rev numarasına koşullu güncellenir
+// (rapor yazılamazsa revizyon geri alınır); araya başka bir yazım girdiyse
+// yeniden okunup denenir. İçerik/saat değişmediyse revizyon açılmaz. Revizyon
+// takibinden önce yazılmış raporlar için önce "baseline" revizyonu kaydedilir.
+func saveReport(ctx context.Context, owner models.User, date string, e reportEdit) (models.Report, error) {
+	for attempt := 0; attempt < maxReportWriteAttempts; attempt++ {
+		rep, err := trySaveReport(ctx, owner, date, e)
+		if err != errReportConflict {
+			return rep, err
+		}
+	}
+	return models.Report{}, errReportConflict
+}
+
+func trySaveReport(ctx context.Context, owner models.User, date string, e reportEdit) (models.Report, error) {
+	content := strings.TrimSpace(e.Content)
+	hours := clampHours(e.Hours)
+	now := time.Now()
+	filter := bson.M{"userId": owner.ID, "date": date}
+
+	var rep, prev models.Report
+	err := db.Col("reports").FindOne(ctx, filter).Decode(&prev)
+	exists := err == nil
+	if err != nil && err != mongo.ErrNoDocuments {
+		return rep, err
+	}
+	if exists && e.OwnerEdit && prev.Status == models.ReportApproved {
+		return rep, errReportApproved
+	}
+
+	contentSame := exists && prev.Content == content && prev.Hours == hours && entriesEqual(prev.Entries, e.Entries)
+	statusSame := e.Status == "" || (exists && prev.EffectiveStatus() == e.Status)
+	if contentSame && statusSame {
+		return prev, nil
+	}
+	if contentSame {
+		// sadece durum değişti (örn. taslağı gönderme): revizyon açılmaz
+		err := db.Col("reports").FindOneAndUpdate(ctx, filter,
+			bson.M{"$set": reportStatusFields(e.Status, now), "$unset": bson.M{"importBatchId": ""}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&rep)
+		return rep, err
+	}
+
+	action := e.Action
+	if action == "" {
+		action = models.RevisionUpdate
+		if !exists {
+			action = models.RevisionCreate
+		}
+	}
+
+	reportID := primitive.NewObjectID()
+	nextRev := 1
+	if exists {
+		// koşullu yazım: okuduğumuz rev hâlâ geçerliyse güncelle
+		reportID = prev.ID
+		filter = bson.M{"_id": prev.ID, "rev": prev.Rev}
+		if prev.Rev == 0 {
+			filter["rev"] = bson.M{"$exists": false}
+			// aynı anda başka bir yazım da baseline eklemiş olabilir
+			if err := insertBaselineRevision(ctx, prev); err != nil && !mongo.IsDuplicateKeyError(err) {
+				return rep, err
+			}
+			prev.Rev = 1
+		}
+		nextRev = prev.Rev + 1
+	} else {
+		// aynı gün için araya başka bir kayıt girdiyse uniq_user_day ile düşer
+		filter = bson.M{"_id": reportID}
+	}
+
+	set := bson.M{
+		"content":   content,
+		"hours":     hours,
+		"userName":  owner.Name,
+		"role":      owner.Role,
+		"rev":       nextRev,
+		"updatedAt": now,
+		"updatedBy": e.Editor.ID,
+	}
+	onInsert := bson.M{
+		"createdAt": now,
+		"userId":    owner.ID,
+		"date":      date,
+	}
+	// uygulamadan düzenlenen rapor artık CSV import geri alımına dahil değil
+	unset := bson.M{"importBatchId": ""}
+	status, target := e.Status, set
+	if status == "" {
+		status, target = models.ReportSubmitted, onInsert
+		if exists && prev.Status == models.ReportApproved {
+			// onay değişen içeriği kapsamaz: rapor yeniden incelemeye düşer
+			target = set
+			for _, f := range []string{"reviewedBy", "reviewedByName", "reviewedAt", "reviewComment"} {
+				unset[f] = ""
+			}
+		}
+	}
+	for k, v := range reportStatusFields(status, now) {
+		target[k] = v
+	}
+	update := bson.M{
+		"$set":         set,
+		"$setOnInsert": onInsert,
+		"$unset":       unset,
+	}
+	// iş kalemsiz yazım raporu tek içerikli hale döndürür
+	if len(e.Entries) > 0 {
+		set["entries"] = e.Entries
+	} else {
+		unset["entries"] = ""
+	}
+
+	r := models.ReportRevision{
+		ID:           primitive.NewObjectID(),
+		ReportID:     reportID,
+		UserID:       owner.ID,
+		Date:         date,
+		Rev:          nextRev,
+		Action:       action,
+		Content:      content,
+		Hours:        hours,
+		Entries:      e.Entries,
+		RestoredFrom: e.RestoredFrom,
+		EditedBy:     e.Editor.ID,
+		EditedByName: e.Editor.Name,
+		EditedAt:     now,
+	}
+	if exists {
+		r.PrevContent = &prev.Content
+		r.PrevHours = &prev.Hours
+		r.PrevEntries = prev.Entries
+	}
+	// önce revizyon: (reportId, rev) tekil olduğundan aynı rev'i yazmaya çalışan
+	// ikinci yazım burada elenir ve rapor hiçbir zaman revizyonsuz ilerlemez
+	if _, err := db.Col("report_revisions").InsertOne(ctx, r); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			dropDanglingRevision(ctx, reportID, nextRev, now)
+			return rep, errReportConflict
+		}
+		return rep, err
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetUpsert(!exists).
+		SetReturnDocument(options.After)
+	err = db.Col("reports").FindOneAndUpdate(ctx, filter, update, opts).Decode(&rep)
+	if err != nil {
+		// rapor yazılamadı: karşılığı olmayan revizyonu geri al
+		if _, derr := db.Col("report_revisions").DeleteOne(context.WithoutCancel(ctx), bson.M{"_id": r.ID}); derr != nil {
+			log.Printf("report %s: revision %d not rolled back: %v", reportID.Hex(), nextRev, derr)
+		}
+		if err == mongo.ErrNoDocuments || mongo.IsDuplicateKeyError(err) {
+			return rep, errReportConflict // araya başka bir yazım girdi
+		}
+		return rep, err
+	}
+	return rep, nil
+}
+
+// Revizyonu eklenip raporu yazılamadan (süreç çökmesi vb.) yarım kalan bir
+// yazımın revizyonu, o rev numarasını kalıcı olarak kilitlemesin diye silinir.
+// Rapor o rev'e hiç ulaşmadıysa ve revizyon danglingRevisionAge'den eskiyse
+// yarım kalmış sayılır; daha yenisi hâlâ süren bir yazıma ait olabilir.
+func dropDanglingRevision(ctx context.Context, reportID primitive.ObjectID, rev int, now time.Time) {
+	n, err := db.Col("reports").CountDocuments(ctx, bson.M{"_id": reportID, "rev": bson.M{"$gte": rev}})
+	if err != nil || n > 0 {
+		return
+	}
+	res, err := db.Col("report_revisions").DeleteOne(ctx, bson.M{
+		"reportId": reportID,
+		"rev":      rev,
+		"editedAt": bson.M{"$lt": now.Add(-danglingRevisionAge)},
+	})
+	if err != nil {
+		log.Printf("report %s: dangling revision %d not removed: %v", reportID.Hex(), rev, err)
+	} else if res.DeletedCount > 0 {
+		log.Printf("report %s: removed dangling revision %d", reportID.Hex(), rev)
+	}
+}
+
+// Revizyon takibi öncesi raporun mevcut halini rev=1 olarak saklar.
+func insertBaselineRevision(ctx context.Context, rep models.Report) error {
+	_, err := db.Col("report_revisions").InsertOne(ctx, rep.BaselineRevision())
+	return err
+}
+
+// :id ile raporu getirir ve erişimi kontrol eder.
+// - rapor sahibi
+// - admin: sahibi kendi departmanındaysa
+// - superadmin: hepsi
+func loadAccessibleReport(c *gin.Context) (models.Report, bool) {
+	ctx := c.Request.Context()
+	oid, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad id"})
+		return models.Report{}, false
+	}
+	var rep models.Report
+	if err := db.Col("reports").FindOne(ctx, bson.M{"_id": oid}).Decode(&rep); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return models.Report{}, false
+	}
+
+	if rep.UserID.Hex() == c.GetString("userId") {
+		return rep, true
+	}
+	switch c.GetString("role") {
+	case string(models.RoleSuperAdmin):
+		return rep, true
+	case string(models.RoleAdmin):
+		n, err := db.Col("users").CountDocuments(ctx, bson.M{"_id": rep.UserID, "departmentId": myDepartmentID(c)})
+		if err == nil && n > 0 {
+			return rep, true
+		}
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	return models.Report{}, false
+}
+
+// Revizyon listesi; legacy raporda hiç revizyon yoksa mevcut hal rev=1 "baseline" gibi döner.
+func reportRevisions(ctx context.Context, rep models.Report) ([]models.ReportRevision, error) {
+	cur, err := db.Col("report_revisions").Find(ctx,
+		bson.M{"reportId": rep.ID},
+		options.Find().SetSort(bson.D{{Key: "rev", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	items := []models.ReportRevision{}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		items = append(items, rep.BaselineRevision())
+	}
+	return items, nil
+}
+
+func findRevision(items []models.ReportRevision, rev int) (models.ReportRevision, bool) {
+	for _, r := range items {
+		if r.Rev == rev {
+			return r, true
+		}
+	}
+	return models.ReportRevision{}, false
+}
+
+// GET /api/reports/:id/revisions  (JWT; sahibi, departman admini, superadmin)
+func ListReportRevisions(c *gin.Context) {
+	rep, ok := loadAccessibleReport(c)
+	if !ok {
+		return
+	}
+	items, err := reportRevisions(c.Request.Context(), rep)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"report": rep, "items": items})
+}
+
+type fieldDiff struct {
+	Field   string      `json:"field"`
+	From    interface{} `json:"from"`
+	To      interface{} `json:"to"`
+	Changed bool        `json:"changed"`
+}
+
+// İki revizyon arasındaki alan bazlı fark.
+func diffRevisions(a, b models.ReportRevision) []fieldDiff {
+	return []fieldDiff{
+		{Field: "content", From: a.Content, To: b.Content, Changed: a.Content != b.Content},
+		{Field: "hours", From: a.Hours, To: b.Hours, Changed: a.Hours != b.Hours},
+		{Field: "entries", From: a.Entries, To: b.Entries, Changed: !entriesEqual(a.Entries, b.Entries)},
+	}
+}
+
+// GET /api/reports/:id/revisions/diff?from=1&to=3
+// to verilmezse son revizyon, from verilmezse to'dan bir önceki.
+func DiffReportRevisions(c *gin.Context) {
+	rep, ok := loadAccessibleReport(c)
+	if !ok {
+		return
+	}
+	items, err := reportRevisions(c.Request.Context(), rep)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	to := items[len(items)-1].Rev
+	if v := strings.TrimSpace(c.Query("to")); v != "" {
+		if to, err = strconv.Atoi(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad to"})
+			return
+		}
+	}
+	from := to - 1
+	if v := strings.TrimSpace(c.Query("from")); v != "" {
+		if from, err = strconv.Atoi(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad from"})
+			return
+		}
+	}
+
+	a, okA := findRevision(items, from)
+	b, okB := findRevision(items, to)
+	if !okA || !okB {
+		c.JSON(http.StatusNotFound, gin.H{"error": "revision not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":    a,
+		"to":      b,
+		"changes": diffRevisions(a, b),
+	})
+}
+
+// POST /api/reports/:id/revisions/:rev/restore  (admin|superadmin)
+// Eski revizyonun değerlerini rapora geri yazar; geri yükleme de yeni bir revizyondur.
+func RestoreReportRevision(c *gin.Context) {
+	ctx := c.Request.Context()
+	rep, ok := loadAccessibleReport(c)
+	if !ok {
+		return
+	}
+	rev, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad rev"})
+		return
+	}
+
+	var owner models.User
+	if err := db.Col("users").FindOne(ctx, bson.M{"_id": rep.UserID}).Decode(&owner); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "report owner not found"})
+		return
+	}
+	if owner.ID.Hex() != c.GetString("userId") && !canModifyUser(c, owner) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	items, err := reportRevisions(ctx, rep)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	target, found := findRevision(items, rev)
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "revision not found"})
+		return
+	}
+
+	me, err := currentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		return
+	}
+	out, err := saveReport(ctx, owner, rep.Date, reportEdit{
+		Content:      target.Content,
+		Hours:        target.Hours,
+		Entries:      target.Entries,
+		Editor:       me,
+		Action:       models.RevisionRestore,
+		RestoredFrom: target.Rev,
+	})
+	if err == errReportConflict {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestDiffRevisions(t *testing.T) {
+	pid := primitive.NewObjectID()
+	entries := []models.ReportEntry{{Description: "api", Hours: 2, ProjectID: &pid, Status: models.EntryDone}}
+	sameEntries := []models.ReportEntry{{Description: "api", Hours: 2, ProjectID: &pid, Status: models.EntryDone}}
+
+	tests := []struct {
+		name    string
+		a, b    models.ReportRevision
+		changed map[string]bool
+	}{
+		{
+			name:    "identical",
+			a:       models.ReportRevision{Content: "x", Hours: 8, Entries: entries},
+			b:       models.ReportRevision{Content: "x", Hours: 8, Entries: sameEntries},
+			changed: map[string]bool{"content": false, "hours": false, "entries": false},
+		},
+		{
+			name:    "content only",
+			a:       models.ReportRevision{Content: "x", Hours: 8},
+			b:       models.ReportRevision{Content: "y", Hours: 8},
+			changed: map[string]bool{"content": true, "hours": false, "entries": false},
+		},
+		{
+			name:    "hours and entries",
+			a:       models.ReportRevision{Content: "x", Hours: 8},
+			b:       models.ReportRevision{Content: "x", Hours: 2, Entries: entries},
+			changed: map[string]bool{"content": false, "hours": true, "entries": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffRevisions(tt.a, tt.b)
+			if len(got) != len(tt.changed) {
+				t.Fatalf("got %d fields, want %d", len(got), len(tt.changed))
+			}
+			for _, d := range got {
+				if want, ok := tt.changed[d.Field]; !ok || d.Changed != want {
+					t.Errorf("%s changed = %v, want %v", d.Field, d.Changed, want)
+				}
+			}
+		})
+	}
+}
+
+// Aşağıdaki testler gerçek bir MongoDB ister (standalone yeterli). TEST_MONGO_URI
+// veritabanı adıyla verilmelidir (örn. mongodb://localhost:27017/rms_test);
+// koleksiyonlar test başında temizlenir.
+func testDB(t *testing.T) context.Context {
+	t.Helper()
+	uri := os.Getenv("TEST_MONGO_URI")
+	if uri == "" {
+		t.Skip("TEST_MONGO_URI not set")
+	}
+	if db.Col("reports") == nil {
+		if err := db.Connect(uri); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+	for _, name := range []string{"users", "reports", "report_revisions"} {
+		if err := db.Col(name).Drop(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.EnsureIndexes(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.EnsureReportRevisionIndexes(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return ctx
+}
+
+func testUser(t *testing.T, ctx context.Context, role models.Role) models.User {
+	t.Helper()
+	u := models.User{ID: primitive.NewObjectID(), Name: string(role), Email: primitive.NewObjectID().Hex() + "@example.com", Role: role}
+	if _, err := db.Col("users").InsertOne(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func loadRevisions(t *testing.T, ctx context.Context, reportID primitive.ObjectID) []models.ReportRevision {
+	t.Helper()
+	cur, err := db.Col("report_revisions").Find(ctx, bson.M{"reportId": reportID},
+		options.Find().SetSort(bson.D{{Key: "rev", Value: 1}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var items []models.ReportRevision
+	if err := cur.All(ctx, &items); err != nil {
+		t.Fatal(err)
+	}
+	return items
+}
+
+func TestSaveReportConcurrentWritesKeepHistory(t *testing.T) {
+	ctx := testDB(t)
+	owner := testUser(t, ctx, models.RoleEmployee)
+	const writers = 8
+
+	var wg sync.WaitGroup
+	errs := make([]error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = saveReport(ctx, owner, "2026-01-05", reportEdit{
+				Content: fmt.Sprintf("writer %d", i), Hours: 8, Editor: owner,
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	saved := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			saved++
+		case !errors.Is(err, errReportConflict):
+			t.Fatalf("saveReport: %v", err)
+		}
+	}
+	if saved == 0 {
+		t.Fatal("no write succeeded")
+	}
+
+	var rep models.Report
+	if err := db.Col("reports").FindOne(ctx, bson.M{"userId": owner.ID, "date": "2026-01-05"}).Decode(&rep); err != nil {
+		t.Fatal(err)
+	}
+	revs := loadRevisions(t, ctx, rep.ID)
+	if len(revs) != saved || rep.Rev != saved {
+		t.Fatalf("rev = %d with %d revisions, want %d", rep.Rev, len(revs), saved)
+	}
+	for i, r := range revs {
+		if r.Rev != i+1 {
+			t.Fatalf("revision %d has rev %d: history has a gap", i, r.Rev)
+		}
+	}
+	if last := revs[len(revs)-1]; last.Content != rep.Content {
+		t.Errorf("last revision content %q, report %q", last.Content, rep.Content)
+	}
+}
+
+func TestSaveReportDropsDanglingRevision(t *testing.T) {
+	ctx := testDB(t)
+	owner := testUser(t, ctx, models.RoleEmployee)
+
+	rep, err := saveReport(ctx, owner, "2026-01-05", reportEdit{Content: "first", Hours: 4, Editor: owner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// rev 2'nin revizyonu eklenmiş ama rapor yazılamadan süreç çökmüş
+	if _, err := db.Col("report_revisions").InsertOne(ctx, models.ReportRevision{
+		ReportID: rep.ID, UserID: owner.ID, Date: rep.Date, Rev: 2, Action: models.RevisionUpdate,
+		Content: "lost", EditedBy: owner.ID, EditedAt: time.Now().Add(-2 * danglingRevisionAge),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	rep, err = saveReport(ctx, owner, "2026-01-05", reportEdit{Content: "second", Hours: 4, Editor: owner})
+	if err != nil {
+		t.Fatalf("saveReport after dangling revision: %v", err)
+	}
+	revs := loadRevisions(t, ctx, rep.ID)
+	if rep.Rev != 2 || len(revs) != 2 || revs[1].Content != "second" {
+		t.Fatalf("rev = %d, revisions = %+v", rep.Rev, revs)
+	}
+}
+
+func TestRestoreReportRevision(t *testing.T) {
+	ctx := testDB(t)
+	gin.SetMode(gin.TestMode)
+	owner := testUser(t, ctx, models.RoleEmployee)
+	admin := testUser(t, ctx, models.RoleSuperAdmin)
+
+	for _, content := range []string{"v1", "v2"} {
+		if _, err := saveReport(ctx, owner, "2026-01-05", reportEdit{Content: content, Hours: 8, Editor: owner}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var rep models.Report
+	if err := db.Col("reports").FindOne(ctx, bson.M{"userId": owner.ID}).Decode(&rep); err != nil {
+		t.Fatal(err)
+	}
+
+	restore := func(rev int) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+		c.Params = gin.Params{{Key: "id", Value: rep.ID.Hex()}, {Key: "rev", Value: strconv.Itoa(rev)}}
+		c.Set("userId", admin.ID.Hex())
+		c.Set("role", string(models.RoleSuperAdmin))
+		RestoreReportRevision(c)
+		return w
+	}
+
+	if w := restore(9); w.Code != http.StatusNotFound {
+		t.Fatalf("restore of missing rev: status %d, want 404", w.Code)
+	}
+	if w := restore(1); w.Code != http.StatusOK {
+		t.Fatalf("restore: status %d: %s", w.Code, w.Body)
+	}
+
+	if err := db.Col("reports").FindOne(ctx, bson.M{"_id": rep.ID}).Decode(&rep); err != nil {
+		t.Fatal(err)
+	}
+	revs := loadRevisions(t, ctx, rep.ID)
+	if rep.Content != "v1" || rep.Rev != 3 || len(revs) != 3 {
+		t.Fatalf("after restore: content %q rev %d revisions %d", rep.Content, rep.Rev, len(revs))
+	}
+	last := revs[2]
+	if last.Action != models.RevisionRestore || last.RestoredFrom != 1 || last.EditedBy != admin.ID ||
+		last.PrevContent == nil || *last.PrevContent != "v2" {
+		t.Errorf("restore revision = %+v", last)
+	}
+}
@@ -22,19 +22,23 @@ func GetReportStatusByDepartment(c *gin.Context) {
 	role := c.GetString("role")
 	dep := strings.TrimSpace(c.Query("department"))
 
+	var depID primitive.ObjectID
 	if role == string(models.RoleAdmin) {
 		// admin ise parametreyi zorla kendi departmanına
-		uid := c.GetString("userId")
-		var me models.User
-		if err := db.Col("users").FindOne(c.Request.Context(), bson.M{"_id": toOID(uid)}).Decode(&me); err == nil {
-			dep = strings.TrimSpace(me.Department)
+		dep, depID = c.GetString("department"), myDepartmentID(c)
+	} else if dep != "" {
+		d, err := resolveDepartment(c.Request.Context(), dep, false)
+		if err != nil {
+			departmentError(c, err)
+			return
 		}
+		dep, depID = d.Name, d.ID
 	}
 
 	// departmandaki kullanıcıları çekiyoruz
-	userFilter := bson.M{}
-	if dep != "" {
-		userFilter["department"] = dep
+	userFilter := activeUserFilter()
+	if !depID.IsZero() {
+		userFilter["departmentId"] = depID
 	}
 	curU, err := db.Col("users").Find(c.Request.Context(), userFilter)
 	if err != nil {
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const defaultUnlockTTL = 72 * time.Hour
+
+// REPORT_UNLOCK_TTL: onaylanan kilit açma izninin geçerlilik süresi (Go duration)
+func reportUnlockTTL() time.Duration { return envDuration("REPORT_UNLOCK_TTL", defaultUnlockTTL) }
+
+// Kullanıcının o gün için onaylanmış ve süresi dolmamış izni var mı?
+func hasActiveUnlock(ctx context.Context, uid primitive.ObjectID, date string) (bool, error) {
+	n, err := db.Col("report_unlocks").CountDocuments(ctx, bson.M{
+		"userId":    uid,
+		"date":      date,
+		"status":    models.UnlockApproved,
+		"expiresAt": bson.M{"$gt": time.Now().UTC()},
+	}, options.Count().SetLimit(1))
+	return n > 0, err
+}
+
+// POST /api/reports/unlocks  {date, reason}  (JWT)
+// Kilitli bir gün için rapor yazma izni ister. Aynı gün için bekleyen talep varsa onu döner.
+func RequestReportUnlock(c *gin.Context) {
+	ctx := c.Request.Context()
+	var body struct {
+		Date   string `json:"date"`
+		Reason string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.Date) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date required"})
+		return
+	}
+	date, ok := reportDateParam(c, body.Date)
+	if !ok {
+		return
+	}
+	if !reportDateLocked(date) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date is within the edit window"})
+		return
+	}
+
+	me, err := currentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		return
+	}
+
+	var existing models.ReportUnlock
+	err = db.Col("report_unlocks").FindOne(ctx, bson.M{
+		"userId": me.ID,
+		"date":   date,
+		"status": models.UnlockPending,
+	}).Decode(&existing)
+	if err == nil {
+		c.JSON(http.StatusOK, existing)
+		return
+	}
+	if err != mongo.ErrNoDocuments {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	u := models.ReportUnlock{
+		UserID:       me.ID,
+		UserName:     me.Name,
+		DepartmentID: me.DepartmentID,
+		Date:         date,
+		Reason:       strings.TrimSpace(body.Reason),
+		Status:       models.UnlockPending,
+		CreatedAt:    time.Now().UTC(),
+	}
+	res, err := db.Col("report_unlocks").InsertOne(ctx, u)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	u.ID, _ = res.InsertedID.(primitive.ObjectID)
+	c.JSON(http.StatusCreated, u)
+}
+
+// GET /api/reports/unlocks?status=pending|approved|rejected  (JWT)
+// - employee: kendi talepleri
+// - admin: kendi departmanı
+// - superadmin: hepsi
+func ListReportUnlocks(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filter := bson.M{}
+	switch c.GetString("role") {
+	case string(models.RoleSuperAdmin):
+	case string(models.RoleAdmin):
+		filter["departmentId"] = myDepartmentID(c)
+	default:
+		filter["userId"] = toOID(c.GetString("userId"))
+	}
+	switch st := models.UnlockStatus(strings.TrimSpace(c.Query("status"))); st {
+	case models.UnlockPending, models.UnlockApproved, models.UnlockRejected:
+		filter["status"] = st
+	}
+
+	cur, err := db.Col("report_unlocks").Find(ctx, filter, optionsFindByDateDesc().SetLimit(500))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer cur.Close(ctx)
+
+	items := []models.ReportUnlock{}
+	if err := cur.All(ctx, &items); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// POST /api/reports/unlocks/:id/approve  (admin|superadmin)
+func ApproveReportUnlock(c *gin.Context) {
+	decideReportUnlock(c, models.UnlockApproved)
+}
+
+// POST /api/reports/unlocks/:id/reject  (admin|superadmin)
+func RejectReportUnlock(c *gin.Context) {
+	decideReportUnlock(c, models.UnlockRejected)
+}
+
+func decideReportUnlock(c *gin.Context, status models.UnlockStatus) {
+	ctx := c.Request.Context()
+	oid, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad id"})
+		return
+	}
+
+	var u models.ReportUnlock
+	if err := db.Col("report_unlocks").FindOne(ctx, bson.M{"_id": oid}).Decode(&u); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	if c.GetString("role") == string(models.RoleAdmin) && u.DepartmentID != myDepartmentID(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	if u.Status != models.UnlockPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "request already " + string(u.Status)})
+		return
+	}
+
+	now := time.Now().UTC()
+	set := bson.M{
+		"status":    status,
+		"decidedBy": toOID(c.GetString("userId")),
+		"decidedAt": now,
+	}
+	if status == models.UnlockApproved {
+		set["expiresAt"] = now.Add(reportUnlockTTL())
+	}
+
+	// pending şartı: aynı anda iki karar verilmesin
+	err = db.Col("report_unlocks").FindOneAndUpdate(ctx,
+		bson.M{"_id": oid, "status": models.UnlockPending},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&u)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusConflict, gin.H{"error": "request already decided"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, u)
+}
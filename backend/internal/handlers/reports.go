@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
-	"regexp"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -22,6 +25,65 @@ import (
 // --- helpers ---
 func todayStr() string { return time.Now().Format("2006-01-02") }
 
+const defaultReportGraceDays = 3
+
+// REPORT_EDIT_GRACE_DAYS: employee'nin kaç gün geriye rapor yazabileceği (0 = sadece bugün)
+func reportGraceDays() int {
+	if v := strings.TrimSpace(os.Getenv("REPORT_EDIT_GRACE_DAYS")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return defaultReportGraceDays
+}
+
+// Grace penceresinin dışında kalan (daha eski) günler kilitlidir.
+func reportDateLocked(date string) bool {
+	cutoff := time.Now().AddDate(0, 0, -reportGraceDays()).Format("2006-01-02")
+	return date < cutoff
+}
+
+// Çalışan, kilitli bir tarihe (açık bir unlock'u yoksa) yazamaz; yanıtı kendisi yazar.
+// Rapor içeriği ve ekleri için aynı kural geçerlidir.
+func requireReportUnlocked(c *gin.Context, u models.User, date string) bool {
+	if u.Role != models.RoleEmployee || !reportDateLocked(date) {
+		return true
+	}
+	unlocked, err := hasActiveUnlock(c.Request.Context(), u.ID, date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !unlocked {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":     "date is locked; request an unlock from your admin",
+			"code":      "REPORT_LOCKED",
+			"graceDays": reportGraceDays(),
+		})
+		return false
+	}
+	return true
+}
+
+// Opsiyonel tarih parametresini doğrular (boşsa bugün). Gelecek tarihler reddedilir.
+func reportDateParam(c *gin.Context, raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return todayStr(), true
+	}
+	d, err := time.ParseInLocation("2006-01-02", raw, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date must be YYYY-MM-DD"})
+		return "", false
+	}
+	date := d.Format("2006-01-02")
+	if date > todayStr() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date is in the future"})
+		return "", false
+	}
+	return date, true
+}
+
 func clampHours(h float64) float64 {
 	if h < 0 {
 		return 0
@@ -32,29 +94,56 @@ func clampHours(h float64) float64 {
 	return h
 }
 
-// Farklı şemaları (userId/uid/user_id) ve tipleri (ObjectID/string) kapsayan kullanıcı filtresi
-func userMatchFilter(uid primitive.ObjectID) bson.M {
-	hex := uid.Hex()
-	return bson.M{
-		"$or": []bson.M{
-			{"userId": uid},
-			{"userId": hex},
-			{"uid": uid},
-			{"uid": hex},
-			{"user_id": uid},
-			{"user_id": hex},
-		},
+// Rapor yazma gövdesi. entries verilirse hours onların toplamıdır ve
+// content boşsa kalemlerden özet üretilir; verilmezse eski tek içerikli format.
+type reportBody struct {
+	Content string               `json:"content"`
+	Hours   float64              `json:"hours"`
+	Entries []models.ReportEntry `json:"entries"`
+	Date    string               `json:"date"`
+	Draft   bool                 `json:"draft"` // true: taslak olarak kaydet, incelemeye gönderme
+}
+
+func (b reportBody) edit(ctx context.Context) (reportEdit, error) {
+	if len(b.Entries) == 0 {
+		if strings.TrimSpace(b.Content) == "" {
+			return reportEdit{}, errors.New("content required")
+		}
+		return reportEdit{Content: b.Content, Hours: b.Hours}, nil
 	}
+	entries, total, err := normalizeEntries(b.Entries)
+	if err != nil {
+		return reportEdit{}, err
+	}
+	if err := resolveEntryProjects(ctx, entries); err != nil {
+		return reportEdit{}, err
+	}
+	content := strings.TrimSpace(b.Content)
+	if content == "" {
+		content = entriesSummary(entries)
+	}
+	return reportEdit{Content: content, Hours: total, Entries: entries}, nil
 }
 
-// POST /api/reports  (JWT) — bugüne rapor upsert
+// POST /api/reports  (JWT) — rapor upsert {content, hours, entries?, date?, draft?}
+// date verilmezse bugün. draft değilse rapor incelemeye gönderilir (submitted);
+// onaylanmış rapor sahibine salt okunurdur. Employee geçmiş günlere REPORT_EDIT_GRACE_DAYS kadar
+// yazabilir; daha eskisi için onaylanmış bir kilit açma talebi gerekir.
+// Admin/superadmin kendi raporlarında pencereyle sınırlı değildir.
 func CreateOrUpdateMyReport(c *gin.Context) {
-	var body struct {
-		Content string  `json:"content"`
-		Hours   float64 `json:"hours"`
+	var body reportBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return
 	}
-	if err := c.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.Content) == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "content required"})
+	edit, err := body.edit(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	date, ok := reportDateParam(c, body.Date)
+	if !ok {
 		return
 	}
 
@@ -72,28 +161,72 @@ func CreateOrUpdateMyReport(c *gin.Context) {
 		return
 	}
 
-	filter := bson.M{"userId": uid, "date": todayStr()}
-	update := bson.M{
-		"$set": bson.M{
-			"content":  strings.TrimSpace(body.Content),
-			"hours":    clampHours(body.Hours),
-			"userName": u.Name,
-			"role":     u.Role,
-		},
-		"$setOnInsert": bson.M{
-			"createdAt": time.Now(),
-			"userId":    uid,
-			"date":      todayStr(),
-		},
+	if !requireReportUnlocked(c, u, date) {
+		return
 	}
-	opts := options.FindOneAndUpdate().
-		SetUpsert(true).
-		SetReturnDocument(options.After)
 
-	var rep models.Report
-	if err := db.Col("reports").
-		FindOneAndUpdate(c.Request.Context(), filter, update, opts).
-		Decode(&rep); err != nil {
+	edit.Editor = u
+	edit.OwnerEdit = true
+	edit.Status = models.ReportSubmitted
+	if body.Draft {
+		edit.Status = models.ReportDraft
+	}
+	rep, err := saveReport(c.Request.Context(), u, date, edit)
+	if err == errReportApproved {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "code": "REPORT_APPROVED"})
+		return
+	}
+	if err == errReportConflict {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rep)
+}
+
+// PUT /api/reports/user/:id/:date  {content, hours, entries?}  (admin|superadmin)
+// Admin override: kilit penceresinden bağımsız olarak bir çalışanın raporunu yazar/düzeltir.
+// Admin sadece kendi departmanındaki employee'ler için kullanabilir.
+func UpsertUserReport(c *gin.Context) {
+	var body reportBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return
+	}
+	edit, err := body.edit(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	date, ok := reportDateParam(c, c.Param("date"))
+	if !ok {
+		return
+	}
+
+	target, ok := loadManagedUser(c)
+	if !ok {
+		return
+	}
+	if !canModifyUser(c, target) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	me, err := currentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		return
+	}
+	edit.Editor = me
+	rep, err := saveReport(c.Request.Context(), target, date, edit)
+	if err == errReportConflict {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -109,13 +242,7 @@ func GetMyTodayReport(c *gin.Context) {
 		return
 	}
 
-	// Hem eski hem yeni şema:
-	filter := bson.M{
-		"$and": []bson.M{
-			userMatchFilter(uid),
-			{"date": todayStr()},
-		},
-	}
+	filter := bson.M{"userId": uid, "date": todayStr()}
 
 	var rep models.Report
 	err = db.Col("reports").
@@ -157,8 +284,8 @@ func GetMyReportsHistory(c *gin.Context) {
 		}
 	}
 
-	// Sadece kullanıcıya göre filtrele — şema/tipe tolerant olsun
-	filter := userMatchFilter(uid)
+	// Sadece kullanıcıya göre filtrele (eski şemalar migration ile normalize edildi)
+	filter := bson.M{"userId": uid}
 
 	cur, err := db.Col("reports").Find(
 		c.Request.Context(),
@@ -174,12 +301,38 @@ func GetMyReportsHistory(c *gin.Context) {
 	}
 	defer cur.Close(c.Request.Context())
 
-	var items []models.Report
-	if err := cur.All(c.Request.Context(), &items); err != nil {
+	var reps []models.Report
+	if err := cur.All(c.Request.Context(), &reps); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"items": items})
+
+	// yorum sayıları sadece sayfadaki raporlar için (maliyet sayfa boyutuyla sınırlı);
+	// unreadComments da bu sayfanın toplamıdır
+	ids := make([]primitive.ObjectID, 0, len(reps))
+	for _, r := range reps {
+		ids = append(ids, r.ID)
+	}
+	counts, err := ownerCommentCounts(c.Request.Context(), uid, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	type historyItem struct {
+		models.Report
+		CommentCount   int `json:"commentCount"`
+		UnreadComments int `json:"unreadComments"`
+	}
+	items := make([]historyItem, 0, len(reps))
+	for _, r := range reps {
+		cc := counts[r.ID]
+		items = append(items, historyItem{Report: r, CommentCount: cc.Total, UnreadComments: cc.Unread})
+	}
+	unread := 0
+	for _, cc := range counts {
+		unread += cc.Unread
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "unreadComments": unread})
 }
 
 // GET /api/reports/user/:id?limit=50&skip=0[&from=YYYY-MM-DD&to=YYYY-MM-DD]
@@ -324,82 +477,9 @@ func GetReportsByDay(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"date": date, "items": out})
 }
 
-// GET /api/reports/search?q=keyword[&department=Dept][&from=YYYY-MM-DD&to=YYYY-MM-DD]
-// (admin/superadmin)
-func SearchReports(c *gin.Context) {
-	q := strings.TrimSpace(c.Query("q"))
-	dep := strings.TrimSpace(c.Query("department"))
-	from := strings.TrimSpace(c.Query("from"))
-	to := strings.TrimSpace(c.Query("to"))
-
-	filter := bson.M{}
-	ands := []bson.M{}
-
-	if q != "" {
-		ands = append(ands, bson.M{"content": bson.M{"$regex": q, "$options": "i"}})
-	}
-
-	if dep != "" {
-		// department için users tablosundan userId set’i çıkar
-		cur, err := db.Col("users").Find(
-			c.Request.Context(),
-			bson.M{"department": dep},
-			options.Find().SetProjection(bson.M{"_id": 1}),
-		)
-		if err == nil {
-			var ids []primitive.ObjectID
-			for cur.Next(c.Request.Context()) {
-				var row struct {
-					ID primitive.ObjectID `bson:"_id"`
-				}
-				_ = cur.Decode(&row)
-				ids = append(ids, row.ID)
-			}
-			_ = cur.Close(c.Request.Context())
-			if len(ids) == 0 {
-				c.JSON(http.StatusOK, gin.H{"items": []any{}})
-				return
-			}
-			ands = append(ands, bson.M{"userId": bson.M{"$in": ids}})
-		}
-	}
-
-	if from != "" || to != "" {
-		dateCond := bson.M{}
-		if from != "" {
-			dateCond["$gte"] = from
-		}
-		if to != "" {
-			dateCond["$lte"] = to
-		}
-		ands = append(ands, bson.M{"date": dateCond})
-	}
-
-	if len(ands) > 0 {
-		filter["$and"] = ands
-	}
-
-	cur, err := db.Col("reports").Find(
-		c.Request.Context(),
-		filter,
-		options.Find().SetSort(bson.D{{Key: "date", Value: -1}}).SetLimit(200),
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	defer cur.Close(c.Request.Context())
-
-	var items []models.Report
-	if err := cur.All(c.Request.Context(), &items); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, gin.H{"items": items})
-}
-
 // GET /api/reports/status?department=Engineering[&date=YYYY-MM-DD]
 // (admin/superadmin)
+// Kişi başı durum: submitted | draft | missing | on_leave | non_working_day
 func GetReportStatus(c *gin.Context) {
 	dep := strings.TrimSpace(c.Query("department"))
 	date := strings.TrimSpace(c.Query("date"))
@@ -410,9 +490,17 @@ func GetReportStatus(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "department is required"})
 		return
 	}
+	dept, err := resolveDepartment(c.Request.Context(), dep, false)
+	if err != nil {
+		departmentError(c, err)
+		return
+	}
+	dep = dept.Name
 
-	// departmandaki kullanıcılar
-	cur, err := db.Col("users").Find(c.Request.Context(), bson.M{"department": dep})
+	// departmandaki (aktif) kullanıcılar
+	cur, err := db.Col("users").Find(c.Request.Context(), bson.M{
+		"$and": []bson.M{{"departmentId": dept.ID}, activeUserFilter()},
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -429,33 +517,18 @@ func GetReportStatus(c *gin.Context) {
 	}
 
 	ids := make([]primitive.ObjectID, 0, len(users))
-	hexIDs := make([]string, 0, len(users))
 	for _, u := range users {
 		ids = append(ids, u.ID)
-		hexIDs = append(hexIDs, u.ID.Hex())
 	}
 
-	// o gün rapor gönderenler (ObjectID veya string ID)
+	// o gün rapor gönderenler
 	rmap := map[primitive.ObjectID]models.Report{}
 	rcur, err := db.Col("reports").Find(
 		c.Request.Context(),
-		bson.M{
-			"$and": []bson.M{
-				{"date": date},
-				{"$or": []bson.M{
-					{"userId": bson.M{"$in": ids}},
-					{"userId": bson.M{"$in": hexIDs}},
-					{"uid": bson.M{"$in": ids}},
-					{"uid": bson.M{"$in": hexIDs}},
-					{"user_id": bson.M{"$in": ids}},
-					{"user_id": bson.M{"$in": hexIDs}},
-				}},
-			},
-		},
+		bson.M{"date": date, "userId": bson.M{"$in": ids}},
 	)
 	if err == nil {
 		for rcur.Next(c.Request.Context()) {
-			// Eski kayıtlarda decode sorun olmasın diye toleranslı oku
 			var r models.Report
 			if err := rcur.Decode(&r); err == nil && r.UserID != primitive.NilObjectID {
 				rmap[r.UserID] = r
@@ -464,38 +537,75 @@ func GetReportStatus(c *gin.Context) {
 		_ = rcur.Close(c.Request.Context())
 	}
 
+	// hafta sonu / tatil / izin
+	wc, err := loadWorkCalendar(c.Request.Context(), date, date, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	workingDay := wc.isWorkingDay(dept.ID, date)
+	holiday, _ := wc.holiday(dept.ID, date)
+
 	type Row struct {
-		UserID   string `json:"userId"`
-		Name     string `json:"name"`
-		HasToday bool   `json:"hasReportToday"`
-		Hours    string `json:"hours,omitempty"`
+		UserID       string              `json:"userId"`
+		Name         string              `json:"name"`
+		HasToday     bool                `json:"hasReportToday"` // taslaklar sayılmaz
+		State        string              `json:"state"`          // submitted|draft|missing|on_leave|non_working_day
+		LeaveType    models.LeaveType    `json:"leaveType,omitempty"`
+		ReviewStatus models.ReportStatus `json:"reviewStatus,omitempty"`
+		Hours        string              `json:"hours,omitempty"`
 	}
 	out := make([]Row, 0, len(users))
+	counts := map[string]int{}
+	expected := 0
 	for _, u := range users {
-		if rep, ok := rmap[u.ID]; ok {
-			hrs := ""
+		if wc.expected(u, date) {
+			expected++
+		}
+		row := Row{UserID: u.ID.Hex(), Name: u.Name}
+		rep, hasRep := rmap[u.ID]
+		if hasRep {
 			if rep.Hours > 0 {
-				hrs = strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", rep.Hours), "0"), ".")
+				row.Hours = strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", rep.Hours), "0"), ".")
 			}
-			out = append(out, Row{
-				UserID:   u.ID.Hex(),
-				Name:     u.Name,
-				HasToday: true,
-				Hours:    hrs,
-			})
-			continue
+			row.HasToday = rep.EffectiveStatus() != models.ReportDraft
+			row.ReviewStatus = rep.EffectiveStatus()
 		}
-		out = append(out, Row{
-			UserID:   u.ID.Hex(),
-			Name:     u.Name,
-			HasToday: false,
-		})
+		leave := wc.leaveOn(u.ID, date)
+		if leave != nil {
+			row.LeaveType = leave.Type
+		}
+		// gönderilmiş rapor izin/tatil gününde de "submitted" sayılır
+		switch {
+		case row.HasToday:
+			row.State = dayStateSubmitted
+		case leave != nil:
+			row.State = dayStateOnLeave
+		case !workingDay:
+			row.State = dayStateNonWorking
+		case hasRep:
+			row.State = dayStateDraft
+		default:
+			row.State = dayStateMissing
+		}
+		counts[row.State]++
+		out = append(out, row)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"date":       date,
 		"department": dep,
+		"workingDay": workingDay,
+		"holiday":    holiday,
 		"items":      out,
+		"summary": gin.H{
+			"submitted": counts[dayStateSubmitted],
+			"draft":     counts[dayStateDraft],
+			"missing":   counts[dayStateMissing],
+			"onLeave":   counts[dayStateOnLeave],
+			// rapor beklenen kişi sayısı (izinliler ve çalışma dışı gün hariç)
+			"expected": expected,
+		},
 	})
 }
 
@@ -558,23 +668,12 @@ func GetDepartmentSeries(c *gin.Context) {
 	// --- yetki / departman doğrulama ---
 	switch role {
 	case string(models.RoleAdmin):
-		uidHex := c.GetString("userId")
-		uid, _ := primitive.ObjectIDFromHex(uidHex)
-		var me models.User
-		if err := db.Col("users").FindOne(ctx, bson.M{"_id": uid}).Decode(&me); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "user lookup failed"})
-			return
-		}
-		if strings.TrimSpace(me.Department) == "" {
+		if myDepartmentID(c).IsZero() {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "user has no department"})
 			return
 		}
 		if dep == "" {
-			dep = me.Department
-		}
-		if dep != me.Department {
-			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
-			return
+			dep = myDepartmentID(c).Hex()
 		}
 	case string(models.RoleSuperAdmin):
 		if dep == "" {
@@ -585,13 +684,22 @@ func GetDepartmentSeries(c *gin.Context) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 		return
 	}
+	dept, err := resolveDepartment(ctx, dep, false)
+	if err != nil {
+		departmentError(c, err)
+		return
+	}
+	if role == string(models.RoleAdmin) && dept.ID != myDepartmentID(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	dep = dept.Name
 
-	// --- departman kullanıcıları (case-insensitive eşleşme) ---
-	depRegex := bson.M{"$regex": "^" + regexp.QuoteMeta(dep) + "$", "$options": "i"}
+	// --- departman kullanıcıları (ID ile) ---
 	ucur, err := db.Col("users").Find(
 		ctx,
-		bson.M{"department": depRegex},
-		options.Find().SetProjection(bson.M{"_id": 1, "name": 1}),
+		bson.M{"departmentId": dept.ID},
+		options.Find().SetProjection(bson.M{"_id": 1, "name": 1, "departmentId": 1, "createdAt": 1, "deactivatedAt": 1}),
 	)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -604,20 +712,24 @@ func GetDepartmentSeries(c *gin.Context) {
 	}
 	if len(users) == 0 {
 		c.JSON(http.StatusOK, gin.H{
-			"series": []any{},
-			"cards":  gin.H{"totalHours": 0, "avgHours": 0, "reportsToday": 0, "activeEmployees": 0},
-			"top":    []any{},
+			"series":     []any{},
+			"categories": []any{},
+			"cards": gin.H{
+				"totalHours": 0, "avgHours": 0, "reportsToday": 0, "activeEmployees": 0,
+				"workingDays": 0, "expectedReports": 0, "submittedReports": 0, "complianceRate": 0,
+			},
+			"top": []any{},
 		})
 		return
 	}
 
 	ids := make([]primitive.ObjectID, 0, len(users))
-	hexIDs := make([]string, 0, len(users))
 	nameBy := map[primitive.ObjectID]string{}
+	userBy := map[primitive.ObjectID]models.User{}
 	for _, u := range users {
 		ids = append(ids, u.ID)
-		hexIDs = append(hexIDs, u.ID.Hex())
 		nameBy[u.ID] = u.Name
+		userBy[u.ID] = u
 	}
 
 	// --- zaman aralığı ---
@@ -647,19 +759,36 @@ func GetDepartmentSeries(c *gin.Context) {
 	fromISO := from.Format("2006-01-02")
 	toISO := todayISO
 
-	// --- raporlar ---
+	// --- çalışma takvimi (hafta sonu, tatil, izin) ---
+	wc, err := loadWorkCalendar(ctx, fromISO, toISO, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	workingDays := 0
+	workingByDate := map[string]bool{}
+	workingByMonth := map[string]int{}
+	eachDate(fromISO, toISO, func(date string) {
+		if wc.isWorkingDay(dept.ID, date) {
+			workingDays++
+			workingByDate[date] = true
+			workingByMonth[date[:7]]++
+		}
+	})
+	expectedReports := 0
+	for _, u := range users {
+		eachDate(fromISO, toISO, func(date string) {
+			if wc.expectedFrom(u, date) {
+				expectedReports++
+			}
+		})
+	}
+
+	// --- raporlar (taslaklar hariç: henüz raporlanmamış sayılır) ---
 	rfilter := bson.M{
-		"$and": []bson.M{
-			{"date": bson.M{"$gte": fromISO, "$lte": toISO}},
-			{"$or": []bson.M{
-				{"userId": bson.M{"$in": ids}},
-				{"userId": bson.M{"$in": hexIDs}},
-				{"uid": bson.M{"$in": ids}},
-				{"uid": bson.M{"$in": hexIDs}},
-				{"user_id": bson.M{"$in": ids}},
-				{"user_id": bson.M{"$in": hexIDs}},
-			}},
-		},
+		"date":   bson.M{"$gte": fromISO, "$lte": toISO},
+		"userId": bson.M{"$in": ids},
+		"status": bson.M{"$ne": models.ReportDraft},
 	}
 	rcur, err := db.Col("reports").Find(ctx, rfilter, options.Find().SetSort(bson.D{{Key: "date", Value: 1}}))
 	if err != nil {
@@ -672,11 +801,14 @@ func GetDepartmentSeries(c *gin.Context) {
 	daily := map[string]float64{}
 	monthlyM := map[string]float64{}
 	totalHours := 0.0
-	reportCnt := 0
+	workHours := 0.0 // sadece çalışma günlerindeki raporlar (ortalama için)
+	workReports := 0
+	submittedReports := 0
 	reportsToday := 0
 	activeUsers := map[primitive.ObjectID]struct{}{}
 	perUserH := map[primitive.ObjectID]float64{}
 	perUserC := map[primitive.ObjectID]int{}
+	catHours := map[string]float64{}
 
 	for rcur.Next(ctx) {
 		var r models.Report
@@ -685,7 +817,13 @@ func GetDepartmentSeries(c *gin.Context) {
 		}
 		h := clampHours(r.Hours)
 		totalHours += h
-		reportCnt++
+		if u, ok := userBy[r.UserID]; ok && wc.expected(u, r.Date) {
+			workHours += h
+			workReports++
+		}
+		if u, ok := userBy[r.UserID]; ok && wc.expectedFrom(u, r.Date) {
+			submittedReports++
+		}
 		if r.Date == todayISO {
 			reportsToday++
 		}
@@ -698,16 +836,26 @@ func GetDepartmentSeries(c *gin.Context) {
 		if len(r.Date) >= 7 {
 			monthlyM[r.Date[:7]] += h
 		}
+		for cat, ch := range reportCategoryHours(r) {
+			catHours[cat] += ch
+		}
 	}
 
+	// ortalama: hafta sonu / tatil / izin günlerindeki raporlar hariç
 	avg := 0.0
-	if reportCnt > 0 {
-		avg = totalHours / float64(reportCnt)
+	if workReports > 0 {
+		avg = workHours / float64(workReports)
+	}
+	compliance := 0.0
+	if expectedReports > 0 {
+		compliance = math.Round(float64(submittedReports)/float64(expectedReports)*1000) / 10
 	}
 
 	type DP struct {
-		Label string  `json:"label"`
-		Hours float64 `json:"hours"`
+		Label       string  `json:"label"`
+		Hours       float64 `json:"hours"`
+		WorkingDay  *bool   `json:"workingDay,omitempty"`  // günlük seri
+		WorkingDays int     `json:"workingDays,omitempty"` // aylık seri
 	}
 	series := []DP{}
 	if monthly {
@@ -719,13 +867,14 @@ func GetDepartmentSeries(c *gin.Context) {
 		for i := months - 1; i >= 0; i-- {
 			d := base.AddDate(0, -i, 0)
 			key := d.Format("2006-01")
-			series = append(series, DP{Label: d.Format("Jan 06"), Hours: monthlyM[key]})
+			series = append(series, DP{Label: d.Format("Jan 06"), Hours: monthlyM[key], WorkingDays: workingByMonth[key]})
 		}
 	} else {
 		for i := days - 1; i >= 0; i-- {
 			d := now.AddDate(0, 0, -i)
 			key := d.Format("2006-01-02")
-			series = append(series, DP{Label: d.Format("02 Jan"), Hours: daily[key]})
+			working := workingByDate[key]
+			series = append(series, DP{Label: d.Format("02 Jan"), Hours: daily[key], WorkingDay: &working})
 		}
 	}
 
@@ -745,13 +894,30 @@ func GetDepartmentSeries(c *gin.Context) {
 		tops = tops[:5]
 	}
 
+	// iş kalemi kategorilerine göre toplam saat (eski raporlar "uncategorized")
+	type Cat struct {
+		Category string  `json:"category"`
+		Hours    float64 `json:"hours"`
+	}
+	cats := make([]Cat, 0, len(catHours))
+	for k, v := range catHours {
+		cats = append(cats, Cat{Category: k, Hours: v})
+	}
+	sort.Slice(cats, func(i, j int) bool { return cats[i].Hours > cats[j].Hours })
+
 	c.JSON(http.StatusOK, gin.H{
-		"series": series,
+		"series":     series,
+		"categories": cats,
 		"cards": gin.H{
 			"totalHours":      totalHours,
 			"avgHours":        avg,
 			"reportsToday":    reportsToday,
 			"activeEmployees": len(activeUsers),
+			// uyum: çalışma günlerinde beklenen raporların yüzde kaçı gönderildi
+			"workingDays":      workingDays,
+			"expectedReports":  expectedReports,
+			"submittedReports": submittedReports,
+			"complianceRate":   compliance,
 		},
 		// geriye uyumluluk (UI başka yerde top-level okuyorsa):
 		"totalHours":        totalHours,
@@ -762,10 +928,12 @@ func GetDepartmentSeries(c *gin.Context) {
 	})
 }
 
-// GET /api/reports/department/breakdown?department=Sales&period=7d|30d|6m|12m&top=5
+// GET /api/reports/department/breakdown?department=Sales&period=7d|30d|6m|12m&top=5[&groupBy=user|category]
+// groupBy=category: seriler kişi yerine iş kalemi kategorisine göre
 func GetDepartmentBreakdown(c *gin.Context) {
 	ctx := c.Request.Context()
 	role := c.GetString("role")
+	byCategory := strings.TrimSpace(c.Query("groupBy")) == "category"
 
 	dep := strings.TrimSpace(c.Query("department"))
 	period := strings.TrimSpace(c.Query("period"))
@@ -776,23 +944,12 @@ func GetDepartmentBreakdown(c *gin.Context) {
 	// --- yetki kontrolü (aynı) ---
 	switch role {
 	case string(models.RoleAdmin):
-		uidHex := c.GetString("userId")
-		uid, _ := primitive.ObjectIDFromHex(uidHex)
-		var me models.User
-		if err := db.Col("users").FindOne(ctx, bson.M{"_id": uid}).Decode(&me); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "user lookup failed"})
-			return
-		}
-		if strings.TrimSpace(me.Department) == "" {
+		if myDepartmentID(c).IsZero() {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "user has no department"})
 			return
 		}
 		if dep == "" {
-			dep = me.Department
-		}
-		if dep != me.Department {
-			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
-			return
+			dep = myDepartmentID(c).Hex()
 		}
 	case string(models.RoleSuperAdmin):
 		if dep == "" {
@@ -803,10 +960,19 @@ func GetDepartmentBreakdown(c *gin.Context) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 		return
 	}
+	dept, err := resolveDepartment(ctx, dep, false)
+	if err != nil {
+		departmentError(c, err)
+		return
+	}
+	if role == string(models.RoleAdmin) && dept.ID != myDepartmentID(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	dep = dept.Name
 
-	// --- departman kullanıcıları (case-insensitive) ---
-	depRegex := bson.M{"$regex": "^" + regexp.QuoteMeta(dep) + "$", "$options": "i"}
-	ucur, err := db.Col("users").Find(ctx, bson.M{"department": depRegex}, options.Find().SetProjection(bson.M{"_id": 1, "name": 1}))
+	// --- departman kullanıcıları (ID ile) ---
+	ucur, err := db.Col("users").Find(ctx, bson.M{"departmentId": dept.ID}, options.Find().SetProjection(bson.M{"_id": 1, "name": 1}))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -822,11 +988,9 @@ func GetDepartmentBreakdown(c *gin.Context) {
 	}
 
 	ids := make([]primitive.ObjectID, 0, len(users))
-	hexIDs := make([]string, 0, len(users))
 	nameBy := map[primitive.ObjectID]string{}
 	for _, u := range users {
 		ids = append(ids, u.ID)
-		hexIDs = append(hexIDs, u.ID.Hex())
 		nameBy[u.ID] = u.Name
 	}
 
@@ -856,19 +1020,11 @@ func GetDepartmentBreakdown(c *gin.Context) {
 	fromISO := from.Format("2006-01-02")
 	toISO := now.Format("2006-01-02")
 
-	// --- raporlar ---
+	// --- raporlar (taslaklar hariç: henüz raporlanmamış sayılır) ---
 	rfilter := bson.M{
-		"$and": []bson.M{
-			{"date": bson.M{"$gte": fromISO, "$lte": toISO}},
-			{"$or": []bson.M{
-				{"userId": bson.M{"$in": ids}},
-				{"userId": bson.M{"$in": hexIDs}},
-				{"uid": bson.M{"$in": ids}},
-				{"uid": bson.M{"$in": hexIDs}},
-				{"user_id": bson.M{"$in": ids}},
-				{"user_id": bson.M{"$in": hexIDs}},
-			}},
-		},
+		"date":   bson.M{"$gte": fromISO, "$lte": toISO},
+		"userId": bson.M{"$in": ids},
+		"status": bson.M{"$ne": models.ReportDraft},
 	}
 	rcur, err := db.Col("reports").Find(ctx, rfilter)
 	if err != nil {
@@ -879,6 +1035,8 @@ func GetDepartmentBreakdown(c *gin.Context) {
 
 	daily := map[string]map[primitive.ObjectID]float64{}
 	totalByUser := map[primitive.ObjectID]float64{}
+	dailyCat := map[string]map[string]float64{}
+	totalByCat := map[string]float64{}
 
 	for rcur.Next(ctx) {
 		var r models.Report
@@ -897,6 +1055,15 @@ func GetDepartmentBreakdown(c *gin.Context) {
 			daily[key][r.UserID] += h
 			totalByUser[r.UserID] += h
 		}
+		if byCategory {
+			if _, ok := dailyCat[key]; !ok {
+				dailyCat[key] = map[string]float64{}
+			}
+			for cat, ch := range reportCategoryHours(r) {
+				dailyCat[key][cat] += ch
+				totalByCat[cat] += ch
+			}
+		}
 	}
 
 	// etiketler
@@ -929,6 +1096,35 @@ func GetDepartmentBreakdown(c *gin.Context) {
 		}
 	}
 
+	if byCategory {
+		type CatSerie struct {
+			Category string    `json:"category"`
+			Points   []float64 `json:"points"`
+			Total    float64   `json:"total"`
+		}
+		out := make([]CatSerie, 0, len(totalByCat))
+		for cat, total := range totalByCat {
+			points := make([]float64, len(keys))
+			for i, k := range keys {
+				points[i] = dailyCat[k][cat]
+			}
+			out = append(out, CatSerie{Category: cat, Points: points, Total: total})
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i].Total > out[j].Total })
+		if top > 0 && len(out) > top {
+			out = out[:top]
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"department": dep,
+			"period":     period,
+			"groupBy":    "category",
+			"labels":     labels,
+			"dates":      keys,
+			"series":     out,
+		})
+		return
+	}
+
 	type kv struct {
 		ID  primitive.ObjectID
 		Val float64
@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const maxSavedSearchesPerUser = 50
+
+// Kaydedilebilecek arama parametreleri (SearchReports ile aynı; sayfalama hariç).
+var savedSearchParamKeys = map[string]bool{
+	"q": true, "department": true, "user": true, "role": true,
+	"minHours": true, "maxHours": true, "hasAttachments": true,
+	"status": true, "project": true, "from": true, "to": true,
+}
+
+func savedSearchValues(params map[string]string) url.Values {
+	v := url.Values{}
+	for k, val := range params {
+		v.Set(k, val)
+	}
+	return v
+}
+
+// Parametreleri temizler ve aramayı kuru çalıştırmadan doğrular.
+func cleanSavedSearchParams(c *gin.Context, in map[string]string) (map[string]string, bool) {
+	out := map[string]string{}
+	for k, v := range in {
+		if !savedSearchParamKeys[k] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown search parameter " + k})
+			return nil, false
+		}
+		if v = strings.TrimSpace(v); v != "" {
+			out[k] = v
+		}
+	}
+	if _, _, err := buildSearchMatch(c.Request.Context(), savedSearchValues(out), requestSearchScope(c)); err != nil {
+		searchMatchError(c, err)
+		return nil, false
+	}
+	return out, true
+}
+
+// Bir sonraki özet zamanı (after'dan kesin sonra); zamanlanmamışsa nil.
+func nextDigestRun(freq models.DigestFrequency, hour, weekday int, after time.Time) *time.Time {
+	if freq == models.DigestNone {
+		return nil
+	}
+	t := time.Date(after.Year(), after.Month(), after.Day(), hour, 0, 0, 0, after.Location())
+	for !t.After(after) || (freq == models.DigestWeekly && int(t.Weekday()) != weekday) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t
+}
+
+type savedSearchBody struct {
+	Name      *string            `json:"name"`
+	Params    *map[string]string `json:"params"`
+	Frequency *string            `json:"frequency"` // ""|daily|weekly
+	Hour      *int               `json:"hour"`
+	Weekday   *int               `json:"weekday"`
+	Email     *bool              `json:"email"`
+}
+
+// Zamanlama alanlarını doğrulayıp s'ye uygular.
+func (b savedSearchBody) applySchedule(c *gin.Context, s *models.SavedSearch) bool {
+	if b.Frequency != nil {
+		switch f := models.DigestFrequency(strings.ToLower(strings.TrimSpace(*b.Frequency))); f {
+		case models.DigestNone, models.DigestDaily, models.DigestWeekly:
+			s.Frequency = f
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "frequency must be daily, weekly or empty"})
+			return false
+		}
+	}
+	if b.Hour != nil {
+		if *b.Hour < 0 || *b.Hour > 23 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "hour must be 0-23"})
+			return false
+		}
+		s.Hour = *b.Hour
+	}
+	if b.Weekday != nil {
+		if *b.Weekday < 0 || *b.Weekday > 6 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "weekday must be 0-6"})
+			return false
+		}
+		s.Weekday = *b.Weekday
+	}
+	if b.Email != nil {
+		s.Email = *b.Email
+	}
+	s.NextRunAt = nextDigestRun(s.Frequency, s.Hour, s.Weekday, time.Now())
+	return true
+}
+
+// GET /api/saved-searches  (admin/superadmin, sadece kendi kayıtları)
+func ListSavedSearches(c *gin.Context) {
+	ctx := c.Request.Context()
+	cur, err := db.Col("saved_searches").Find(ctx,
+		bson.M{"ownerId": toOID(c.GetString("userId"))},
+		options.Find().SetSort(bson.D{{Key: "name", Value: 1}}),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	items := []models.SavedSearch{}
+	if err := cur.All(ctx, &items); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// POST /api/saved-searches
+// body: { name, params: {q, department, ...}, frequency: ""|daily|weekly, hour, weekday, email }
+func CreateSavedSearch(c *gin.Context) {
+	ctx := c.Request.Context()
+	var body savedSearchBody
+	if err := c.ShouldBindJSON(&body); err != nil || body.Name == nil || strings.TrimSpace(*body.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name required"})
+		return
+	}
+	owner := toOID(c.GetString("userId"))
+
+	n, err := db.Col("saved_searches").CountDocuments(ctx, bson.M{"ownerId": owner})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if n >= maxSavedSearchesPerUser {
+		c.JSON(http.StatusConflict, gin.H{"error": "saved search limit reached", "max": maxSavedSearchesPerUser})
+		return
+	}
+
+	params := map[string]string{}
+	if body.Params != nil {
+		var ok bool
+		if params, ok = cleanSavedSearchParams(c, *body.Params); !ok {
+			return
+		}
+	}
+
+	now := time.Now()
+	s := models.SavedSearch{
+		OwnerID:   owner,
+		Name:      strings.TrimSpace(*body.Name),
+		Params:    params,
+		Hour:      8,
+		Weekday:   int(time.Monday),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if !body.applySchedule(c, &s) {
+		return
+	}
+	// ilk özet, kayıttan sonraki yeni eşleşmeleri kapsar
+	s.LastRunAt = &now
+
+	res, err := db.Col("saved_searches").InsertOne(ctx, s)
+	if mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "a saved search with this name already exists"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	s.ID = res.InsertedID.(primitive.ObjectID)
+	c.JSON(http.StatusCreated, s)
+}
+
+func loadMySavedSearch(c *gin.Context) (models.SavedSearch, bool) {
+	var s models.SavedSearch
+	oid, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad id"})
+		return s, false
+	}
+	err = db.Col("saved_searches").FindOne(c.Request.Context(),
+		bson.M{"_id": oid, "ownerId": toOID(c.GetString("userId"))}).Decode(&s)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "saved search not found"})
+		return s, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return s, false
+	}
+	return s, true
+}
+
+// PATCH /api/saved-searches/:id  (alanlar opsiyonel)
+func UpdateSavedSearch(c *gin.Context) {
+	ctx := c.Request.Context()
+	s, ok := loadMySavedSearch(c)
+	if !ok {
+		return
+	}
+	var body savedSearchBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return
+	}
+	if body.Name != nil {
+		if strings.TrimSpace(*body.Name) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name required"})
+			return
+		}
+		s.Name = strings.TrimSpace(*body.Name)
+	}
+	if body.Params != nil {
+		if s.Params, ok = cleanSavedSearchParams(c, *body.Params); !ok {
+			return
+		}
+	}
+	if !body.applySchedule(c, &s) {
+		return
+	}
+	s.UpdatedAt = time.Now()
+
+	set := bson.M{
+		"name": s.Name, "params": s.Params, "frequency": s.Frequency,
+		"hour": s.Hour, "weekday": s.Weekday, "email": s.Email, "updatedAt": s.UpdatedAt,
+	}
+	update := bson.M{"$set": set}
+	if s.NextRunAt != nil {
+		set["nextRunAt"] = *s.NextRunAt
+	} else {
+		update["$unset"] = bson.M{"nextRunAt": ""}
+	}
+	_, err := db.Col("saved_searches").UpdateByID(ctx, s.ID, update)
+	if mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "a saved search with this name already exists"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
+
+// DELETE /api/saved-searches/:id
+func DeleteSavedSearch(c *gin.Context) {
+	s, ok := loadMySavedSearch(c)
+	if !ok {
+		return
+	}
+	if _, err := db.Col("saved_searches").DeleteOne(c.Request.Context(), bson.M{"_id": s.ID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// POST /api/saved-searches/:id/run
+// Özeti hemen üretir (son çalıştırmadan bu yana yeni eşleşmeler) ve pencereyi ilerletir.
+func RunSavedSearch(c *gin.Context) {
+	s, ok := loadMySavedSearch(c)
+	if !ok {
+		return
+	}
+	res, err := runSearchDigest(c.Request.Context(), s, time.Now())
+	if err != nil {
+		searchMatchError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,570 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxSearchQueryLen = 256
+	snippetRadius     = 80 // eşleşmenin iki yanında kaç karakter
+)
+
+// Sayfalama imleci: son öğenin sıralama anahtarları (base64 JSON, istemci için opak).
+type searchCursor struct {
+	Score float64 `json:"s,omitempty"`
+	Date  string  `json:"d,omitempty"`
+	ID    string  `json:"i"`
+}
+
+func (sc searchCursor) encode() string {
+	b, _ := json.Marshal(sc)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeSearchCursor(raw string) (searchCursor, primitive.ObjectID, error) {
+	var sc searchCursor
+	b, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return sc, primitive.NilObjectID, err
+	}
+	if err := json.Unmarshal(b, &sc); err != nil {
+		return sc, primitive.NilObjectID, err
+	}
+	oid, err := primitive.ObjectIDFromHex(sc.ID)
+	return sc, oid, err
+}
+
+type snippetPart struct {
+	Text  string `json:"text"`
+	Match bool   `json:"match,omitempty"`
+}
+
+type searchHit struct {
+	models.Report
+	Department   string        `json:"department,omitempty"`
+	DepartmentID string        `json:"departmentId,omitempty"`
+	Score        float64       `json:"score,omitempty"`
+	Snippet      string        `json:"snippet,omitempty"`
+	SnippetParts []snippetPart `json:"snippetParts,omitempty"`
+}
+
+// $text sorgusundan vurgulanacak terimleri çıkarır: "ifadeler" bütün olarak,
+// -olumsuz terimler hariç.
+func searchTerms(q string) []string {
+	var terms []string
+	for {
+		i := strings.IndexByte(q, '"')
+		if i < 0 {
+			break
+		}
+		j := strings.IndexByte(q[i+1:], '"')
+		if j < 0 {
+			break
+		}
+		if i == 0 || q[i-1] != '-' {
+			if p := strings.TrimSpace(q[i+1 : i+1+j]); p != "" {
+				terms = append(terms, p)
+			}
+		}
+		q = q[:i] + " " + q[i+2+j:]
+	}
+	for _, w := range strings.Fields(q) {
+		if strings.HasPrefix(w, "-") {
+			continue
+		}
+		w = strings.TrimFunc(w, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		if w != "" {
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
+
+// İçerikten ilk eşleşme çevresinde kısa bir parça üretir ve terimleri işaretler.
+// Eşleşme yoksa (ör. kök eşleşmesi) içeriğin başı döner.
+func buildSnippet(content string, terms []string) (string, []snippetPart) {
+	lower := strings.ToLower(content)
+	first := -1
+	for _, t := range terms {
+		if i := strings.Index(lower, strings.ToLower(t)); i >= 0 && (first < 0 || i < first) {
+			first = i
+		}
+	}
+
+	start, end := 0, len(content)
+	if first > snippetRadius {
+		start = first - snippetRadius
+	}
+	if end-start > 2*snippetRadius+40 {
+		end = start + 2*snippetRadius + 40
+	}
+	// UTF-8 karakter sınırına hizala
+	for start > 0 && !utf8.RuneStart(content[start]) {
+		start--
+	}
+	for end < len(content) && !utf8.RuneStart(content[end]) {
+		end++
+	}
+	snippet := content[start:end]
+	prefix, suffix := "", ""
+	if start > 0 {
+		prefix = "…"
+	}
+	if end < len(content) {
+		suffix = "…"
+	}
+
+	// vurgulama: parça içinde terimlerin geçtiği aralıkları işaretle
+	ls := strings.ToLower(snippet)
+	marked := make([]bool, len(snippet))
+	for _, t := range terms {
+		lt := strings.ToLower(t)
+		if lt == "" || len(lt) != len(t) {
+			continue // ToLower bayt uzunluğunu değiştirdiyse aralıklar kayar
+		}
+		for off := 0; ; {
+			i := strings.Index(ls[off:], lt)
+			if i < 0 {
+				break
+			}
+			for k := off + i; k < off+i+len(lt); k++ {
+				marked[k] = true
+			}
+			off += i + len(lt)
+		}
+	}
+
+	parts := []snippetPart{}
+	if prefix != "" {
+		parts = append(parts, snippetPart{Text: prefix})
+	}
+	for i := 0; i < len(snippet); {
+		j := i
+		for j < len(snippet) && marked[j] == marked[i] {
+			j++
+		}
+		parts = append(parts, snippetPart{Text: snippet[i:j], Match: marked[i]})
+		i = j
+	}
+	if suffix != "" {
+		parts = append(parts, snippetPart{Text: suffix})
+	}
+	return prefix + snippet + suffix, parts
+}
+
+// Arama parametresi hatası (400 döner).
+type searchParamError string
+
+func (e searchParamError) Error() string { return string(e) }
+
+// Aramayı kimin adına çalıştırdığımız: admin her zaman kendi departmanıyla sınırlıdır.
+type searchScope struct {
+	Role         string
+	DepartmentID primitive.ObjectID
+}
+
+func requestSearchScope(c *gin.Context) searchScope {
+	return searchScope{Role: c.GetString("role"), DepartmentID: myDepartmentID(c)}
+}
+
+// buildSearchMatch hatasını yanıta çevirir.
+func searchMatchError(c *gin.Context, err error) {
+	switch err.(type) {
+	case searchParamError:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		departmentError(c, err)
+	}
+}
+
+// Virgülle ayrılmış ya da tekrarlanan parametre (?user=a,b&user=c).
+func paramList(params url.Values, key string) []string {
+	var out []string
+	for _, v := range params[key] {
+		for _, p := range strings.Split(v, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				out = append(out, p)
+			}
+		}
+	}
+	return out
+}
+
+func paramFloat(params url.Values, key string) (*float64, error) {
+	v := strings.TrimSpace(params.Get(key))
+	if v == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Arama parametrelerini $match'e çevirir (SearchReports ve kayıtlı aramalar ortak kullanır).
+// empty=true: filtre hiçbir kullanıcıyla eşleşemez (boş sonuç).
+func buildSearchMatch(ctx context.Context, params url.Values, scope searchScope) (match bson.M, empty bool, err error) {
+	q := strings.TrimSpace(params.Get("q"))
+	if len(q) > maxSearchQueryLen {
+		return nil, false, searchParamError("query too long")
+	}
+	match = bson.M{}
+	if q != "" {
+		match["$text"] = bson.M{"$search": q}
+	}
+
+	// --- kullanıcı kümesi: departman ∩ user listesi ---
+	var userIDs []primitive.ObjectID
+	userScoped := false
+
+	dep := strings.TrimSpace(params.Get("department"))
+	if scope.Role == string(models.RoleAdmin) {
+		if scope.DepartmentID.IsZero() {
+			return match, true, nil
+		}
+		dep = scope.DepartmentID.Hex()
+	}
+	if dep != "" {
+		// arşivlenmiş departmanların geçmiş raporları da aranabilir
+		dept, err := resolveDepartment(ctx, dep, false)
+		if err != nil {
+			return nil, false, err
+		}
+		// department için users tablosundan userId set’i çıkar
+		cur, err := db.Col("users").Find(ctx,
+			bson.M{"departmentId": dept.ID},
+			options.Find().SetProjection(bson.M{"_id": 1}),
+		)
+		if err != nil {
+			return nil, false, err
+		}
+		var users []models.User
+		if err := cur.All(ctx, &users); err != nil {
+			return nil, false, err
+		}
+		for _, u := range users {
+			userIDs = append(userIDs, u.ID)
+		}
+		userScoped = true
+	}
+
+	if list := paramList(params, "user"); len(list) > 0 {
+		want := make([]primitive.ObjectID, 0, len(list))
+		for _, h := range list {
+			oid, err := primitive.ObjectIDFromHex(h)
+			if err != nil {
+				return nil, false, searchParamError("bad user id")
+			}
+			want = append(want, oid)
+		}
+		if userScoped {
+			in := make(map[primitive.ObjectID]bool, len(userIDs))
+			for _, id := range userIDs {
+				in[id] = true
+			}
+			both := []primitive.ObjectID{}
+			for _, id := range want {
+				if in[id] {
+					both = append(both, id)
+				}
+			}
+			want = both
+		}
+		userIDs, userScoped = want, true
+	}
+	if userScoped {
+		if len(userIDs) == 0 {
+			return match, true, nil
+		}
+		match["userId"] = bson.M{"$in": userIDs}
+	}
+
+	// --- tarih ---
+	from := strings.TrimSpace(params.Get("from"))
+	to := strings.TrimSpace(params.Get("to"))
+	if from != "" || to != "" {
+		dateCond := bson.M{}
+		if from != "" {
+			dateCond["$gte"] = from
+		}
+		if to != "" {
+			dateCond["$lte"] = to
+		}
+		match["date"] = dateCond
+	}
+
+	// --- rol (rapordaki denormalize rol) ---
+	if roles := paramList(params, "role"); len(roles) > 0 {
+		match["role"] = bson.M{"$in": roles}
+	}
+
+	// --- saat aralığı (saat alanı olmayan eski kayıtlar 0 sayılır) ---
+	minH, err := paramFloat(params, "minHours")
+	if err != nil {
+		return nil, false, searchParamError("bad minHours")
+	}
+	maxH, err := paramFloat(params, "maxHours")
+	if err != nil {
+		return nil, false, searchParamError("bad maxHours")
+	}
+	if minH != nil || maxH != nil {
+		hc := bson.M{}
+		if minH != nil {
+			hc["$gte"] = *minH
+		}
+		if maxH != nil {
+			hc["$not"] = bson.M{"$gt": *maxH}
+		}
+		match["hours"] = hc
+	}
+
+	// --- ek dosya ---
+	switch strings.ToLower(strings.TrimSpace(params.Get("hasAttachments"))) {
+	case "1", "true", "yes":
+		match["attachments.0"] = bson.M{"$exists": true}
+	case "0", "false", "no":
+		match["attachments.0"] = bson.M{"$exists": false}
+	}
+
+	// --- onay durumu (status'suz eski kayıtlar submitted sayılır) ---
+	if list := paramList(params, "status"); len(list) > 0 {
+		in := []interface{}{}
+		for _, st := range list {
+			switch models.ReportStatus(st) {
+			case models.ReportSubmitted:
+				in = append(in, st, nil)
+			case models.ReportDraft, models.ReportApproved, models.ReportChangesRequested:
+				in = append(in, st)
+			default:
+				return nil, false, searchParamError("invalid status")
+			}
+		}
+		match["status"] = bson.M{"$in": in}
+	}
+
+	// --- proje (ID veya kod) ---
+	if list := paramList(params, "project"); len(list) > 0 {
+		ids := make([]primitive.ObjectID, 0, len(list))
+		for _, ref := range list {
+			if oid, err := primitive.ObjectIDFromHex(ref); err == nil {
+				ids = append(ids, oid)
+				continue
+			}
+			var p models.Project
+			if err := db.Col("projects").FindOne(ctx, bson.M{"code": normalizeProjectCode(ref)}).Decode(&p); err != nil {
+				return nil, false, searchParamError("unknown project " + ref)
+			}
+			ids = append(ids, p.ID)
+		}
+		match["entries.projectId"] = bson.M{"$in": ids}
+	}
+
+	return match, false, nil
+}
+
+type facetBucket struct {
+	ID    string `json:"id,omitempty" bson:"id"`
+	Name  string `json:"name"         bson:"name"`
+	Count int    `json:"count"        bson:"count"`
+}
+
+type searchFacets struct {
+	Departments []facetBucket `json:"departments" bson:"departments"`
+	Users       []facetBucket `json:"users"       bson:"users"`
+	Months      []facetBucket `json:"months"      bson:"months"`
+}
+
+const maxUserFacets = 50
+
+// Eşleşen tüm raporlar üzerinden departman / kullanıcı / ay sayıları (tek $facet sorgusu).
+func searchFacetCounts(ctx context.Context, match bson.M) (searchFacets, error) {
+	out := searchFacets{Departments: []facetBucket{}, Users: []facetBucket{}, Months: []facetBucket{}}
+	cur, err := db.Col("reports").Aggregate(ctx, []bson.M{
+		{"$match": match},
+		{"$project": bson.M{"userId": 1, "userName": 1, "date": 1}},
+		{"$facet": bson.M{
+			"departments": []bson.M{
+				{"$group": bson.M{"_id": "$userId", "n": bson.M{"$sum": 1}}},
+				{"$lookup": bson.M{"from": "users", "localField": "_id", "foreignField": "_id", "as": "u"}},
+				{"$group": bson.M{
+					"_id":   bson.M{"$arrayElemAt": []interface{}{"$u.departmentId", 0}},
+					"name":  bson.M{"$first": bson.M{"$arrayElemAt": []interface{}{"$u.department", 0}}},
+					"count": bson.M{"$sum": "$n"},
+				}},
+				{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "name", Value: 1}}},
+				{"$project": bson.M{"_id": 0, "id": bson.M{"$toString": "$_id"}, "name": bson.M{"$ifNull": []interface{}{"$name", ""}}, "count": 1}},
+			},
+			"users": []bson.M{
+				{"$group": bson.M{"_id": "$userId", "name": bson.M{"$first": "$userName"}, "count": bson.M{"$sum": 1}}},
+				{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "name", Value: 1}}},
+				{"$limit": maxUserFacets},
+				{"$project": bson.M{"_id": 0, "id": bson.M{"$toString": "$_id"}, "name": bson.M{"$ifNull": []interface{}{"$name", ""}}, "count": 1}},
+			},
+			"months": []bson.M{
+				{"$group": bson.M{"_id": bson.M{"$substr": []interface{}{"$date", 0, 7}}, "count": bson.M{"$sum": 1}}},
+				{"$sort": bson.M{"_id": -1}},
+				{"$project": bson.M{"_id": 0, "name": "$_id", "count": 1}},
+			},
+		}},
+	})
+	if err != nil {
+		return out, err
+	}
+	defer cur.Close(ctx)
+	if cur.Next(ctx) {
+		if err := cur.Decode(&out); err != nil {
+			return out, err
+		}
+	}
+	return out, cur.Err()
+}
+
+// GET /api/reports/search  (admin/superadmin)
+//
+//	q=...                          MongoDB $text sözdizimi — "tam ifade", -hariç
+//	department=Dept                (admin: her zaman kendi departmanı)
+//	user=<id>[,<id>...]            kullanıcı(lar)
+//	role=employee|admin|superadmin
+//	minHours=&maxHours=            saat aralığı (kayıtsız saat 0 sayılır)
+//	hasAttachments=true|false
+//	status=draft|submitted|approved|changes_requested[,...]
+//	project=<id|code>[,...]
+//	from=YYYY-MM-DD&to=YYYY-MM-DD
+//	limit=50&cursor=...
+//
+// q varsa alaka puanına, yoksa tarihe göre sıralanır. Sonraki sayfa için yanıttaki
+// nextCursor gönderilir. İlk sayfada (cursor yokken) departman/kullanıcı/ay facet sayıları döner.
+func SearchReports(c *gin.Context) {
+	ctx := c.Request.Context()
+	q := strings.TrimSpace(c.Query("q"))
+
+	limit := int64(50)
+	if v := strings.TrimSpace(c.Query("limit")); v != "" {
+		if n, e := strconv.ParseInt(v, 10, 64); e == nil && n > 0 && n <= 200 {
+			limit = n
+		}
+	}
+
+	match, empty, err := buildSearchMatch(ctx, c.Request.URL.Query(), requestSearchScope(c))
+	if err != nil {
+		searchMatchError(c, err)
+		return
+	}
+	if empty {
+		c.JSON(http.StatusOK, gin.H{
+			"items":      []any{},
+			"nextCursor": nil,
+			"facets":     searchFacets{Departments: []facetBucket{}, Users: []facetBucket{}, Months: []facetBucket{}},
+		})
+		return
+	}
+	rawCursor := strings.TrimSpace(c.Query("cursor"))
+
+	// sıralama: alaka (score, _id) ya da tarih (date, _id), azalan
+	pipeline := []bson.M{{"$match": match}}
+	sort := bson.D{{Key: "date", Value: -1}, {Key: "_id", Value: -1}}
+	if q != "" {
+		pipeline = append(pipeline, bson.M{"$addFields": bson.M{"score": bson.M{"$meta": "textScore"}}})
+		sort = bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: -1}}
+	}
+	if rawCursor != "" {
+		cur, oid, err := decodeSearchCursor(rawCursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad cursor"})
+			return
+		}
+		key, val := "date", interface{}(cur.Date)
+		if q != "" {
+			key, val = "score", cur.Score
+		}
+		pipeline = append(pipeline, bson.M{"$match": bson.M{"$or": []bson.M{
+			{key: bson.M{"$lt": val}},
+			{key: val, "_id": bson.M{"$lt": oid}},
+		}}})
+	}
+	pipeline = append(pipeline,
+		bson.M{"$sort": sort},
+		bson.M{"$limit": limit + 1},
+		bson.M{"$lookup": bson.M{
+			"from":         "users",
+			"localField":   "userId",
+			"foreignField": "_id",
+			"as":           "u",
+		}},
+		bson.M{"$addFields": bson.M{
+			"userName":     bson.M{"$ifNull": []interface{}{bson.M{"$arrayElemAt": []interface{}{"$u.name", 0}}, "$userName"}},
+			"department":   bson.M{"$arrayElemAt": []interface{}{"$u.department", 0}},
+			"departmentId": bson.M{"$arrayElemAt": []interface{}{"$u.departmentId", 0}},
+		}},
+		bson.M{"$project": bson.M{"u": 0}},
+	)
+
+	cur, err := db.Col("reports").Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer cur.Close(ctx)
+
+	terms := searchTerms(q)
+	items := []searchHit{}
+	for cur.Next(ctx) {
+		var row struct {
+			models.Report `bson:",inline"`
+			Department    string             `bson:"department"`
+			DepartmentID  primitive.ObjectID `bson:"departmentId"`
+			Score         float64            `bson:"score"`
+		}
+		if err := cur.Decode(&row); err != nil {
+			continue
+		}
+		hit := searchHit{
+			Report:       row.Report,
+			Department:   row.Department,
+			DepartmentID: hexOrEmpty(row.DepartmentID),
+			Score:        row.Score,
+		}
+		if q != "" {
+			hit.Snippet, hit.SnippetParts = buildSnippet(row.Content, terms)
+		}
+		items = append(items, hit)
+	}
+
+	var next interface{}
+	if int64(len(items)) > limit {
+		items = items[:limit]
+		last := items[len(items)-1]
+		nc := searchCursor{ID: last.ID.Hex(), Date: last.Date}
+		if q != "" {
+			nc = searchCursor{ID: last.ID.Hex(), Score: last.Score}
+		}
+		next = nc.encode()
+	}
+	resp := gin.H{"items": items, "nextCursor": next}
+	if rawCursor == "" {
+		facets, err := searchFacetCounts(ctx, match)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		resp["facets"] = facets
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/mailer"
+	"report-management-system/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultDigestPollInterval = time.Minute
+	maxDigestItems            = 20 // bildirim/e-postada listelenen eşleşme sayısı
+)
+
+type digestResult struct {
+	Matches        int64  `json:"matches"`
+	NotificationID string `json:"notificationId,omitempty"`
+	Emailed        bool   `json:"emailed"`
+	EmailError     string `json:"emailError,omitempty"`
+}
+
+// Kayıtlı aramayı sahibinin yetkileriyle çalıştırır; son çalıştırmadan (LastRunAt)
+// bu yana oluşturulan/düzenlenen eşleşmeleri bildirim (+ opsiyonel e-posta) olarak gönderir.
+func runSearchDigest(ctx context.Context, s models.SavedSearch, now time.Time) (digestResult, error) {
+	var res digestResult
+
+	var owner models.User
+	if err := db.Col("users").FindOne(ctx, bson.M{"_id": s.OwnerID}).Decode(&owner); err != nil {
+		return res, fmt.Errorf("owner lookup: %w", err)
+	}
+	if !owner.Active() || owner.Role == models.RoleEmployee {
+		return res, errors.New("owner can no longer run searches")
+	}
+
+	match, empty, err := buildSearchMatch(ctx, savedSearchValues(s.Params),
+		searchScope{Role: string(owner.Role), DepartmentID: owner.DepartmentID})
+	if err != nil {
+		return res, err
+	}
+
+	since := s.CreatedAt
+	if s.LastRunAt != nil {
+		since = *s.LastRunAt
+	}
+	var hits []models.Report
+	if !empty {
+		window := bson.M{"$gt": since, "$lte": now}
+		match["$and"] = []bson.M{{"$or": []bson.M{
+			{"createdAt": window},
+			{"updatedAt": window},
+		}}}
+
+		if res.Matches, err = db.Col("reports").CountDocuments(ctx, match); err != nil {
+			return res, err
+		}
+		if res.Matches > 0 {
+			cur, err := db.Col("reports").Find(ctx, match, options.Find().
+				SetSort(bson.D{{Key: "date", Value: -1}, {Key: "_id", Value: -1}}).
+				SetLimit(maxDigestItems).
+				SetProjection(bson.M{"userName": 1, "date": 1, "content": 1, "hours": 1}))
+			if err != nil {
+				return res, err
+			}
+			if err := cur.All(ctx, &hits); err != nil {
+				return res, err
+			}
+		}
+	}
+
+	if res.Matches > 0 {
+		title := fmt.Sprintf("%d new match(es) for \"%s\"", res.Matches, s.Name)
+		body := digestText(s, hits, res.Matches)
+		ids := make([]primitive.ObjectID, 0, len(hits))
+		for _, h := range hits {
+			ids = append(ids, h.ID)
+		}
+		nid, err := notify(ctx, models.Notification{
+			UserID:    owner.ID,
+			Type:      models.NotificationSearchDigest,
+			Title:     title,
+			Body:      body,
+			SourceID:  &s.ID,
+			ReportIDs: ids,
+			CreatedAt: now,
+		})
+		if err != nil {
+			return res, err
+		}
+		res.NotificationID = nid.Hex()
+
+		// e-posta hatası özeti iptal etmez; kayda düşülür
+		if s.Email && owner.Email != "" {
+			if err := mailer.Send([]string{owner.Email}, "[Reports] "+title, body); err != nil {
+				res.EmailError = err.Error()
+			} else {
+				res.Emailed = true
+			}
+		}
+	}
+
+	set := bson.M{"lastRunAt": now, "lastMatches": res.Matches, "lastError": res.EmailError}
+	update := bson.M{"$set": set}
+	if next := nextDigestRun(s.Frequency, s.Hour, s.Weekday, now); next != nil {
+		set["nextRunAt"] = *next
+	} else {
+		update["$unset"] = bson.M{"nextRunAt": ""}
+	}
+	_, err = db.Col("saved_searches").UpdateByID(ctx, s.ID, update)
+	return res, err
+}
+
+func digestText(s models.SavedSearch, hits []models.Report, total int64) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Saved search \"%s\": %d new or updated report(s).\n\n", s.Name, total)
+	terms := searchTerms(s.Params["q"])
+	for _, h := range hits {
+		snippet, _ := buildSnippet(h.Content, terms)
+		fmt.Fprintf(&b, "- %s  %s: %s\n", h.Date, h.UserName, strings.Join(strings.Fields(snippet), " "))
+	}
+	if total > int64(len(hits)) {
+		fmt.Fprintf(&b, "\n... and %d more.\n", total-int64(len(hits)))
+	}
+	return b.String()
+}
+
+// Zamanı gelen kayıtlı aramaları tek tek sahiplenip çalıştırır. nextRunAt önce
+// ileri alındığı için birden fazla sunucu örneği aynı özeti iki kez göndermez.
+func runDueSearchDigests(ctx context.Context, now time.Time) {
+	col := db.Col("saved_searches")
+	for {
+		var s models.SavedSearch
+		err := col.FindOneAndUpdate(ctx,
+			bson.M{"nextRunAt": bson.M{"$lte": now}},
+			bson.M{"$set": bson.M{"nextRunAt": now.Add(time.Hour)}}, // çalışma çökerse 1 saat sonra tekrar denenir
+			options.FindOneAndUpdate().SetSort(bson.M{"nextRunAt": 1}),
+		).Decode(&s)
+		if err == mongo.ErrNoDocuments {
+			return
+		}
+		if err != nil {
+			log.Println("search digest:", err)
+			return
+		}
+		if _, err := runSearchDigest(ctx, s, now); err != nil {
+			log.Printf("search digest %s: %v", s.ID.Hex(), err)
+			next := nextDigestRun(s.Frequency, s.Hour, s.Weekday, now)
+			set := bson.M{"lastError": err.Error()}
+			if next != nil {
+				set["nextRunAt"] = *next
+			}
+			_, _ = col.UpdateByID(ctx, s.ID, bson.M{"$set": set})
+		}
+	}
+}
+
+// StartSearchDigests: zamanlanmış özetleri SEARCH_DIGEST_POLL aralığıyla (varsayılan 1m)
+// kontrol eder; ctx iptal edilince durur.
+func StartSearchDigests(ctx context.Context) {
+	t := time.NewTicker(envDuration("SEARCH_DIGEST_POLL", defaultDigestPollInterval))
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-t.C:
+			runDueSearchDigests(ctx, now)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/middleware"
+	"report-management-system/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Login/refresh yanıtındaki token alanları
+func tokenPayload(access string, accessExp time.Time, refresh string, sessExp time.Time) gin.H {
+	return gin.H{
+		"token":            access,
+		"expiresAt":        accessExp,
+		"refreshToken":     refresh,
+		"refreshExpiresAt": sessExp,
+	}
+}
+
+// Yeni session açar, access + refresh token döner.
+func issueSession(c *gin.Context, u models.User) (gin.H, error) {
+	raw, hash, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	s := models.Session{
+		ID:          primitive.NewObjectID(),
+		UserID:      u.ID,
+		RefreshHash: hash,
+		UserAgent:   c.Request.UserAgent(),
+		IP:          c.ClientIP(),
+		CreatedAt:   now,
+		LastUsedAt:  now,
+		ExpiresAt:   now.Add(refreshTokenTTL()),
+	}
+	if _, err := db.Col("sessions").InsertOne(c.Request.Context(), s); err != nil {
+		return nil, err
+	}
+
+	access, exp, err := signAccessToken(u, s.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	return tokenPayload(access, exp, raw, s.ExpiresAt), nil
+}
+
+// Kullanıcının tüm oturumlarını kapatır ve token versiyonunu artırır;
+// böylece elde kalan access token'lar da anında geçersiz olur.
+func revokeUserSessions(ctx context.Context, uid primitive.ObjectID) error {
+	if _, err := db.Col("users").UpdateByID(ctx, uid, bson.M{"$inc": bson.M{"tokenVersion": 1}}); err != nil {
+		return err
+	}
+	middleware.InvalidateUser(uid)
+	_, err := db.Col("sessions").UpdateMany(ctx,
+		bson.M{"userId": uid, "revokedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revokedAt": time.Now().UTC()}},
+	)
+	return err
+}
+
+// POST /api/auth/refresh  {refreshToken}
+// Refresh token tek kullanımlıktır: her çağrıda yenisi döner. Eski bir token
+// tekrar gelirse (çalınmış olabilir) ilgili session tamamen iptal edilir.
+func Refresh(c *gin.Context) {
+	var body struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.RefreshToken) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "refreshToken required"})
+		return
+	}
+	ctx := c.Request.Context()
+	now := time.Now().UTC()
+	oldHash := hashToken(body.RefreshToken)
+
+	raw, newHash, err := newOpaqueToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "token error"})
+		return
+	}
+
+	var s models.Session
+	err = db.Col("sessions").FindOneAndUpdate(ctx,
+		bson.M{
+			"refreshHash": oldHash,
+			"revokedAt":   bson.M{"$exists": false},
+			"expiresAt":   bson.M{"$gt": now},
+		},
+		bson.M{"$set": bson.M{
+			"refreshHash":     newHash,
+			"prevRefreshHash": oldHash,
+			"lastUsedAt":      now,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&s)
+	if err == mongo.ErrNoDocuments {
+		// reuse tespiti: önceki token tekrar kullanıldıysa session'ı kapat
+		_, _ = db.Col("sessions").UpdateOne(ctx,
+			bson.M{"prevRefreshHash": oldHash, "revokedAt": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"revokedAt": now}},
+		)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh token"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var u models.User
+	if err := db.Col("users").FindOne(ctx, bson.M{"_id": s.UserID}).Decode(&u); err != nil {
+		_, _ = db.Col("sessions").UpdateByID(ctx, s.ID, bson.M{"$set": bson.M{"revokedAt": now}})
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized", "code": "USER_NOT_FOUND"})
+		return
+	}
+	if !u.Active() {
+		_, _ = db.Col("sessions").UpdateByID(ctx, s.ID, bson.M{"$set": bson.M{"revokedAt": now}})
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized", "code": "USER_DEACTIVATED"})
+		return
+	}
+
+	access, exp, err := signAccessToken(u, s.ID, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "token error"})
+		return
+	}
+	c.JSON(http.StatusOK, tokenPayload(access, exp, raw, s.ExpiresAt))
+}
+
+// POST /api/auth/logout  {refreshToken} — refresh token'ın oturumunu kapatır.
+// Access token'ın süresi dolmuş olsa da çalışır (istemci çıkışı). Gövdede refresh
+// token yoksa JWT ile devam edilir (Logout).
+func LogoutByRefreshToken(c *gin.Context) {
+	var body struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+	_ = c.ShouldBindJSON(&body)
+	if strings.TrimSpace(body.RefreshToken) == "" {
+		return
+	}
+	// bilinmeyen/iptal edilmiş token da başarılı sayılır: oturum zaten kapalı
+	_, err := db.Col("sessions").UpdateOne(c.Request.Context(),
+		bson.M{"refreshHash": hashToken(body.RefreshToken), "revokedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revokedAt": time.Now().UTC()}},
+	)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.AbortWithStatusJSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// POST /api/auth/logout  (JWT) — mevcut oturumu kapatır
+func Logout(c *gin.Context) {
+	sid, err := primitive.ObjectIDFromHex(c.GetString("sessionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad session"})
+		return
+	}
+	_, err = db.Col("sessions").UpdateOne(c.Request.Context(),
+		bson.M{"_id": sid, "revokedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revokedAt": time.Now().UTC()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// POST /api/auth/logout-all  (JWT) — tüm cihazlardan çıkış
+func LogoutAll(c *gin.Context) {
+	uid, err := primitive.ObjectIDFromHex(c.GetString("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad user id"})
+		return
+	}
+	if err := revokeUserSessions(c.Request.Context(), uid); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
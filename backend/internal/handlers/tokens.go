@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"strings"
+	"time"
+
+	"report-management-system/internal/models"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// ACCESS_TOKEN_TTL / REFRESH_TOKEN_TTL: Go duration formatı (örn. "15m", "720h")
+func envDuration(key string, def time.Duration) time.Duration {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return def
+}
+
+func accessTokenTTL() time.Duration  { return envDuration("ACCESS_TOKEN_TTL", defaultAccessTokenTTL) }
+func refreshTokenTTL() time.Duration { return envDuration("REFRESH_TOKEN_TTL", defaultRefreshTokenTTL) }
+
+// Rastgele, URL-safe opak token (davet, refresh vb.) ve DB'de saklanacak hash'i.
+func newOpaqueToken() (raw, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", err
+	}
+	raw = base64.RawURLEncoding.EncodeToString(b)
+	return raw, hashToken(raw), nil
+}
+
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(raw)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Kısa ömürlü access token: session (sid) ve token versiyonuna (tv) bağlı.
+func signAccessToken(u models.User, sid primitive.ObjectID, now time.Time) (string, time.Time, error) {
+	exp := now.Add(accessTokenTTL())
+	claims := jwt.MapClaims{
+		"id":   u.ID.Hex(),
+		"role": string(u.Role),
+		"sid":  sid.Hex(),
+		"tv":   u.TokenVersion,
+		"iat":  now.Unix(),
+		"exp":  exp.Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	str, err := token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	return str, exp, err
+}
@@ -2,69 +2,396 @@ package handlers
 
 import (
 	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"report-management-system/internal/db"
+	"report-management-system/internal/middleware"
 	"report-management-system/internal/models"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// GET /api/users?department=...   (admin|superadmin)
+type userDTO struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Email         string     `json:"email"`
+	Role          string     `json:"role"`
+	DepartmentID  string     `json:"departmentId,omitempty"`
+	Department    string     `json:"department"`
+	Active        bool       `json:"active"`
+	CreatedAt     time.Time  `json:"createdAt,omitempty"`
+	DeactivatedAt *time.Time `json:"deactivatedAt,omitempty"`
+}
+
+func toUserDTO(u models.User) userDTO {
+	return userDTO{
+		ID:            u.ID.Hex(),
+		Name:          u.Name,
+		Email:         u.Email,
+		Role:          string(u.Role),
+		DepartmentID:  hexOrEmpty(u.DepartmentID),
+		Department:    u.Department,
+		Active:        u.Active(),
+		CreatedAt:     u.CreatedAt,
+		DeactivatedAt: u.DeactivatedAt,
+	}
+}
+
+// Deaktif kullanıcıları dışarıda bırakan filtre (durum ekranı, headcount vb.)
+func activeUserFilter() bson.M {
+	return bson.M{"deactivatedAt": bson.M{"$exists": false}}
+}
+
+func hexOrEmpty(id primitive.ObjectID) string {
+	if id.IsZero() {
+		return ""
+	}
+	return id.Hex()
+}
+
+// GET /api/users?department=...&q=...&role=...&status=active|inactive|all&limit=50&skip=0
+// (admin|superadmin)
 // Admin: sadece kendi departmanı; Superadmin: parametre zorunlu değil (hepsi)
 func GetUsersByDepartment(c *gin.Context) {
+	ctx := c.Request.Context()
 	role := c.GetString("role")
 	dep := strings.TrimSpace(c.Query("department"))
+	var depID primitive.ObjectID
 
 	if role == string(models.RoleAdmin) {
-		// admin'in kendi departmanını bul
-		uid := c.GetString("userId")
-		var me models.User
-		if err := db.Col("users").FindOne(c.Request.Context(), bson.M{"_id": toOID(uid)}).Decode(&me); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "user not found"})
+		// admin: kendi departmanı (middleware güncel kayıttan set eder)
+		dep, depID = c.GetString("department"), myDepartmentID(c)
+		if depID.IsZero() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "user has no department"})
+			return
+		}
+	} else if dep != "" {
+		d, err := resolveDepartment(ctx, dep, false)
+		if err != nil {
+			departmentError(c, err)
 			return
 		}
-		dep = strings.TrimSpace(me.Department)
+		dep, depID = d.Name, d.ID
+	}
+
+	limit := int64(50)
+	skip := int64(0)
+	if v := strings.TrimSpace(c.Query("limit")); v != "" {
+		if n, e := strconv.ParseInt(v, 10, 64); e == nil && n > 0 && n <= 200 {
+			limit = n
+		}
+	}
+	if v := strings.TrimSpace(c.Query("skip")); v != "" {
+		if n, e := strconv.ParseInt(v, 10, 64); e == nil && n >= 0 {
+			skip = n
+		}
 	}
 
+	ands := []bson.M{}
+	if !depID.IsZero() {
+		ands = append(ands, bson.M{"departmentId": depID})
+	}
+	if r := strings.ToLower(strings.TrimSpace(c.Query("role"))); r != "" {
+		ands = append(ands, bson.M{"role": r})
+	}
+	switch strings.TrimSpace(c.Query("status")) {
+	case "inactive":
+		ands = append(ands, bson.M{"deactivatedAt": bson.M{"$exists": true}})
+	case "all":
+	default:
+		ands = append(ands, activeUserFilter())
+	}
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		// kullanıcı girdisi regex olarak yorumlanmasın
+		rx := bson.M{"$regex": regexp.QuoteMeta(q), "$options": "i"}
+		ands = append(ands, bson.M{"$or": []bson.M{
+			{"name": rx},
+			{"email": rx},
+		}})
+	}
 	filter := bson.M{}
-	if dep != "" {
-		filter["department"] = dep
+	if len(ands) > 0 {
+		filter["$and"] = ands
 	}
 
-	cur, err := db.Col("users").Find(c.Request.Context(), filter)
+	total, err := db.Col("users").CountDocuments(ctx, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	defer cur.Close(c.Request.Context())
 
-	type UserDTO struct {
-		ID         string `json:"id"`
-		Name       string `json:"name"`
-		Email      string `json:"email"`
-		Role       string `json:"role"`
-		Department string `json:"department"`
+	cur, err := db.Col("users").Find(ctx, filter,
+		options.Find().
+			SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}).
+			SetLimit(limit).
+			SetSkip(skip),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
-	var out []UserDTO
-	for cur.Next(c.Request.Context()) {
+	defer cur.Close(ctx)
+
+	out := []userDTO{}
+	for cur.Next(ctx) {
 		var u models.User
 		if err := cur.Decode(&u); err == nil {
-			out = append(out, UserDTO{
-				ID:         u.ID.Hex(),
-				Name:       u.Name,
-				Email:      u.Email,
-				Role:       string(u.Role),
-				Department: u.Department,
-			})
+			out = append(out, toUserDTO(u))
 		}
 	}
-	c.JSON(http.StatusOK, gin.H{"items": out, "department": dep})
+	c.JSON(http.StatusOK, gin.H{
+		"items":      out,
+		"department": dep,
+		"total":      total,
+		"limit":      limit,
+		"skip":       skip,
+	})
+}
+
+// Hedef kullanıcıyı getirir ve admin için departman kapsamını uygular.
+// Hata durumunda yanıtı yazar ve false döner.
+func loadManagedUser(c *gin.Context) (models.User, bool) {
+	oid, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad user id"})
+		return models.User{}, false
+	}
+	var u models.User
+	if err := db.Col("users").FindOne(c.Request.Context(), bson.M{"_id": oid}).Decode(&u); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return models.User{}, false
+	}
+	if c.GetString("role") == string(models.RoleAdmin) && u.DepartmentID != myDepartmentID(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return models.User{}, false
+	}
+	return u, true
+}
+
+// Admin sadece kendi departmanındaki employee'leri değiştirebilir.
+func canModifyUser(c *gin.Context, target models.User) bool {
+	if c.GetString("role") == string(models.RoleSuperAdmin) {
+		return true
+	}
+	return target.Role == models.RoleEmployee
+}
+
+// GET /api/users/:id  (admin kendi departmanı, superadmin hepsi)
+func GetUser(c *gin.Context) {
+	u, ok := loadManagedUser(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, toUserDTO(u))
+}
+
+// PATCH /api/users/:id  {name?, email?, role?, department?}
+// - admin: kendi departmanındaki employee'lerin sadece adı/e-postası
+// - superadmin: her alan (kendi rolünü değiştiremez)
+func UpdateUser(c *gin.Context) {
+	var body struct {
+		Name       *string `json:"name"`
+		Email      *string `json:"email"`
+		Role       *string `json:"role"`
+		Department *string `json:"department"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return
+	}
+
+	target, ok := loadManagedUser(c)
+	if !ok {
+		return
+	}
+	if !canModifyUser(c, target) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	isSuper := c.GetString("role") == string(models.RoleSuperAdmin)
+
+	set := bson.M{}
+	if body.Name != nil {
+		name := strings.TrimSpace(*body.Name)
+		if name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name cannot be empty"})
+			return
+		}
+		set["name"] = name
+	}
+	if body.Email != nil {
+		email := strings.ToLower(strings.TrimSpace(*body.Email))
+		if email == "" || !strings.Contains(email, "@") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid email"})
+			return
+		}
+		set["email"] = email
+	}
+	if body.Role != nil {
+		if !isSuper {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only superadmin can change roles"})
+			return
+		}
+		var role models.Role
+		switch strings.ToLower(strings.TrimSpace(*body.Role)) {
+		case "employee":
+			role = models.RoleEmployee
+		case "admin":
+			role = models.RoleAdmin
+		case "superadmin":
+			role = models.RoleSuperAdmin
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid role"})
+			return
+		}
+		if target.ID.Hex() == c.GetString("userId") && role != target.Role {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cannot change your own role"})
+			return
+		}
+		set["role"] = role
+	}
+	if body.Department != nil {
+		if !isSuper {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only superadmin can move users between departments"})
+			return
+		}
+		d, err := resolveDepartment(c.Request.Context(), *body.Department, true)
+		if err != nil {
+			departmentError(c, err)
+			return
+		}
+		set["departmentId"] = d.ID
+		set["department"] = d.Name
+	}
+	if len(set) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "nothing to update"})
+		return
+	}
+
+	var u models.User
+	err := db.Col("users").FindOneAndUpdate(c.Request.Context(),
+		bson.M{"_id": target.ID},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&u)
+	if mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "email already exists"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// rol/departman anında geçerli olsun
+	middleware.InvalidateUser(u.ID)
+
+	// raporlardaki denormalize ad/rol alanlarını güncel tut
+	repSet := bson.M{}
+	if _, ok := set["name"]; ok {
+		repSet["userName"] = u.Name
+	}
+	if _, ok := set["role"]; ok {
+		repSet["role"] = u.Role
+	}
+	if len(repSet) > 0 {
+		_, _ = db.Col("reports").UpdateMany(c.Request.Context(), bson.M{"userId": u.ID}, bson.M{"$set": repSet})
+	}
+
+	c.JSON(http.StatusOK, toUserDTO(u))
+}
+
+// POST /api/users/:id/deactivate  — girişi engeller, tüm oturumları kapatır
+func DeactivateUser(c *gin.Context) {
+	setUserActive(c, false)
+}
+
+// POST /api/users/:id/reactivate
+func ReactivateUser(c *gin.Context) {
+	setUserActive(c, true)
+}
+
+func setUserActive(c *gin.Context, active bool) {
+	ctx := c.Request.Context()
+	target, ok := loadManagedUser(c)
+	if !ok {
+		return
+	}
+	if !canModifyUser(c, target) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	if target.ID.Hex() == c.GetString("userId") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot change your own status"})
+		return
+	}
+
+	var update bson.M
+	if active {
+		update = bson.M{"$unset": bson.M{"deactivatedAt": ""}}
+	} else {
+		update = bson.M{"$set": bson.M{"deactivatedAt": time.Now().UTC()}}
+	}
+
+	var u models.User
+	if err := db.Col("users").FindOneAndUpdate(ctx, bson.M{"_id": target.ID}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&u); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !active {
+		if err := revokeUserSessions(ctx, u.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	middleware.InvalidateUser(u.ID)
+	c.JSON(http.StatusOK, toUserDTO(u))
+}
+
+// DELETE /api/users/:id  — kullanıcıyı siler; geçmiş raporlar korunur
+func DeleteUser(c *gin.Context) {
+	ctx := c.Request.Context()
+	target, ok := loadManagedUser(c)
+	if !ok {
+		return
+	}
+	if !canModifyUser(c, target) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	if target.ID.Hex() == c.GetString("userId") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot delete yourself"})
+		return
+	}
+
+	if _, err := db.Col("users").DeleteOne(ctx, bson.M{"_id": target.ID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	_, _ = db.Col("sessions").UpdateMany(ctx,
+		bson.M{"userId": target.ID, "revokedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revokedAt": time.Now().UTC()}},
+	)
+	middleware.InvalidateUser(target.ID)
+	c.JSON(http.StatusOK, gin.H{"ok": true})
 }
 
 func toOID(hex string) primitive.ObjectID {
 	oid, _ := primitive.ObjectIDFromHex(hex)
 	return oid
 }
+
+// JWT'deki userId ile oturum sahibini getirir.
+func currentUser(c *gin.Context) (models.User, error) {
+	var me models.User
+	err := db.Col("users").FindOne(c.Request.Context(), bson.M{"_id": toOID(c.GetString("userId"))}).Decode(&me)
+	return me, err
+}
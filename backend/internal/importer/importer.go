@@ -0,0 +1,243 @@
+package importer
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxRowErrors = 500 // yanıtta listelenen en fazla satır hatası
+	writeChunk   = 500 // toplu yazım boyutu
+)
+
+// CSV içe aktarma ayarları.
+type Options struct {
+	// Hedef alan -> CSV başlığı (örn. "email": "E-Mail"). Verilmeyen alanlar için
+	// alan adıyla aynı başlık (büyük/küçük harf duyarsız) aranır.
+	Mapping map[string]string
+	// Tarih sütunu için Go layout'u; varsayılan 2006-01-02
+	DateFormat string
+	// Kuru çalıştırma: sadece doğrula, hiçbir şey yazma
+	DryRun bool
+	// Geçersiz satırları atlayıp geçerlileri yaz (varsayılan: tek hata bile varsa yazma)
+	SkipInvalid bool
+
+	Source    string             // dosya adı (kayıt için)
+	CreatedBy primitive.ObjectID // HTTP'den geliyorsa superadmin; CLI'da boş
+}
+
+type RowError struct {
+	Row   int    `json:"row"` // CSV satır numarası (başlık = 1)
+	Field string `json:"field,omitempty"`
+	Error string `json:"error"`
+}
+
+type Result struct {
+	BatchID         string     `json:"batchId,omitempty"`
+	Kind            string     `json:"kind"`
+	DryRun          bool       `json:"dryRun"`
+	Rows            int        `json:"rows"`
+	Valid           int        `json:"valid"`
+	Invalid         int        `json:"invalid"`
+	Inserted        int        `json:"inserted"`
+	Updated         int        `json:"updated"`
+	Errors          []RowError `json:"errors"`
+	ErrorsTruncated bool       `json:"errorsTruncated,omitempty"`
+}
+
+func (r *Result) addError(row int, field, msg string) {
+	if len(r.Errors) >= maxRowErrors {
+		r.ErrorsTruncated = true
+		return
+	}
+	r.Errors = append(r.Errors, RowError{Row: row, Field: field, Error: msg})
+}
+
+// Başlık eşlemesi çözülmüş CSV okuyucu.
+type table struct {
+	r    *csv.Reader
+	cols map[string]int // alan -> sütun indeksi
+	line int
+}
+
+func openTable(r io.Reader, fields []string, required []string, mapping map[string]string) (*table, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: read header: %v", ErrBadInput, err)
+	}
+	idx := map[string]int{}
+	for i, h := range header {
+		if i == 0 {
+			h = strings.TrimPrefix(h, "\ufeff")
+		}
+		idx[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	known := map[string]bool{}
+	for _, f := range fields {
+		known[f] = true
+	}
+	for f := range mapping {
+		if !known[f] {
+			return nil, fmt.Errorf("%w: unknown field %q in mapping (fields: %s)", ErrBadInput, f, strings.Join(fields, ", "))
+		}
+	}
+
+	t := &table{r: cr, cols: map[string]int{}, line: 1}
+	for _, f := range fields {
+		name := f
+		if m, ok := mapping[f]; ok && strings.TrimSpace(m) != "" {
+			name = m
+		}
+		if i, ok := idx[strings.ToLower(strings.TrimSpace(name))]; ok {
+			t.cols[f] = i
+		} else if _, mapped := mapping[f]; mapped {
+			return nil, fmt.Errorf("%w: column %q (mapped to %s) not found in header", ErrBadInput, name, f)
+		}
+	}
+	for _, f := range required {
+		if _, ok := t.cols[f]; !ok {
+			return nil, fmt.Errorf("%w: required column %q not found (use a mapping)", ErrBadInput, f)
+		}
+	}
+	return t, nil
+}
+
+// Sonraki kayıt; dosya sonunda io.EOF.
+func (t *table) next() (map[string]string, error) {
+	rec, err := t.r.Read()
+	if err == io.EOF {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadInput, err)
+	}
+	t.line, _ = t.r.FieldPos(0)
+	row := make(map[string]string, len(t.cols))
+	for f, i := range t.cols {
+		if i < len(rec) {
+			row[f] = strings.TrimSpace(rec[i])
+		}
+	}
+	return row, nil
+}
+
+func blankRow(row map[string]string) bool {
+	for _, v := range row {
+		if v != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Departman adı veya ID'si -> aktif departman (aynı içe aktarmada önbelleklenir).
+type deptCache map[string]*models.Department
+
+func (dc deptCache) resolve(ctx context.Context, ref string) (*models.Department, error) {
+	key := strings.ToLower(strings.TrimSpace(ref))
+	if d, ok := dc[key]; ok {
+		return d, nil
+	}
+	var d models.Department
+	var err error
+	if oid, e := primitive.ObjectIDFromHex(key); e == nil {
+		err = db.Col("departments").FindOne(ctx, bson.M{"_id": oid, "active": true}).Decode(&d)
+	} else {
+		err = db.Col("departments").FindOne(ctx, bson.M{"name": strings.TrimSpace(ref), "active": true},
+			options.FindOne().SetCollation(&options.Collation{Locale: "en", Strength: 2}),
+		).Decode(&d)
+	}
+	if err == mongo.ErrNoDocuments {
+		dc[key] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	dc[key] = &d
+	return &d, nil
+}
+
+func newBatch(ctx context.Context, kind models.ImportKind, opt Options, res *Result) (models.ImportBatch, error) {
+	b := models.ImportBatch{
+		ID:        primitive.NewObjectID(),
+		Kind:      kind,
+		Source:    opt.Source,
+		Status:    models.ImportCompleted,
+		CreatedBy: opt.CreatedBy,
+		CreatedAt: time.Now(),
+	}
+	res.BatchID = b.ID.Hex()
+	_, err := db.Col("import_batches").InsertOne(ctx, b)
+	return b, err
+}
+
+func finishBatch(ctx context.Context, id primitive.ObjectID, res *Result) error {
+	_, err := db.Col("import_batches").UpdateByID(ctx, id, bson.M{"$set": bson.M{
+		"rows":     res.Rows,
+		"inserted": res.Inserted,
+		"updated":  res.Updated,
+		"skipped":  res.Invalid,
+	}})
+	return err
+}
+
+// Üzerine yazılacak mevcut kayıtların önceki halini saklar (geri alma bunları geri yükler).
+// Aynı batch içinde ikinci kez yazılan kayıt için ilk yedek korunur.
+func backup(ctx context.Context, batchID primitive.ObjectID, collection string, docs []bson.Raw) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	ops := make([]mongo.WriteModel, 0, len(docs))
+	for _, d := range docs {
+		id, ok := d.Lookup("_id").ObjectIDOK()
+		if !ok {
+			continue
+		}
+		ops = append(ops, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"batchId": batchID, "docId": id}).
+			SetUpdate(bson.M{"$setOnInsert": bson.M{"collection": collection, "doc": d}}).
+			SetUpsert(true))
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+	_, err := db.Col("import_backups").BulkWrite(ctx, ops, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+var (
+	// Dosya/eşleme hatası (başlık okunamadı, sütun yok, bozuk CSV)
+	ErrBadInput          = errors.New("bad import file")
+	ErrBatchNotFound     = errors.New("import batch not found")
+	errMissingUserColumn = fmt.Errorf(`%w: required column "email" or "userId" not found (use a mapping)`, ErrBadInput)
+)
+
+func ListBatches(ctx context.Context, limit int64) ([]models.ImportBatch, error) {
+	cur, err := db.Col("import_batches").Find(ctx, bson.M{},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	items := []models.ImportBatch{}
+	err = cur.All(ctx, &items)
+	return items, err
+}
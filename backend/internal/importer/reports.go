@@ -0,0 +1,294 @@
+package importer
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ReportFields = []string{"email", "userId", "date", "hours", "content", "status"}
+
+// Import ve geri alım revizyonlarında düzenleyen adı (CLI'da editedBy boştur).
+const importEditorName = "CSV import"
+
+type reportRow struct {
+	line    int
+	user    *models.User
+	date    string
+	hours   float64
+	content string
+	status  models.ReportStatus
+}
+
+func (r reportRow) key() string { return r.user.ID.Hex() + "|" + r.date }
+
+// Kullanıcı e-posta veya ID ile bulunur (aynı içe aktarmada önbelleklenir).
+// Deaktif kullanıcılar da kabul edilir: geçmiş kayıtlar eski çalışanlara ait olabilir.
+type userCache map[string]*models.User
+
+func (uc userCache) resolve(ctx context.Context, email, id string) (*models.User, error) {
+	key, filter := "e:"+strings.ToLower(email), bson.M{"email": strings.ToLower(email)}
+	if id != "" {
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, nil
+		}
+		key, filter = "i:"+id, bson.M{"_id": oid}
+	}
+	if u, ok := uc[key]; ok {
+		return u, nil
+	}
+	var u models.User
+	err := db.Col("users").FindOne(ctx, filter).Decode(&u)
+	if err == mongo.ErrNoDocuments {
+		uc[key] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	uc[key] = &u
+	return &u, nil
+}
+
+// "7.5", "7,5" veya boş (0)
+func parseHours(s string) (float64, bool) {
+	if s == "" {
+		return 0, true
+	}
+	h, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", "."), 64)
+	if err != nil || h < 0 || h > 24 {
+		return 0, false
+	}
+	return h, true
+}
+
+// Raporları (userId, date) anahtarına göre ekler/günceller (uniq_user_day ile aynı anahtar).
+// Var olan raporun önceki hali yedeklenir; iş kalemleri varsa kaldırılır (içerik + saat yazılır).
+func ImportReports(ctx context.Context, r io.Reader, opt Options) (*Result, error) {
+	res := &Result{Kind: string(models.ImportReports), DryRun: opt.DryRun, Errors: []RowError{}}
+	t, err := openTable(r, ReportFields, []string{"date"}, opt.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	_, hasEmail := t.cols["email"]
+	_, hasID := t.cols["userId"]
+	if !hasEmail && !hasID {
+		return nil, errMissingUserColumn
+	}
+	layout := opt.DateFormat
+	if layout == "" {
+		layout = "2006-01-02"
+	}
+	today := time.Now().Format("2006-01-02")
+
+	users := userCache{}
+	seen := map[string]int{}
+	var rows []reportRow
+	for {
+		rec, err := t.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if blankRow(rec) {
+			continue
+		}
+		res.Rows++
+
+		row := reportRow{line: t.line, content: rec["content"], status: models.ReportSubmitted}
+		bad := false
+		fail := func(field, msg string) {
+			res.addError(row.line, field, msg)
+			bad = true
+		}
+
+		if rec["email"] == "" && rec["userId"] == "" {
+			fail("email", "email or userId required")
+		} else if u, err := users.resolve(ctx, rec["email"], rec["userId"]); err != nil {
+			return nil, err
+		} else if u == nil {
+			fail("email", "unknown user")
+		} else {
+			row.user = u
+		}
+
+		if d, err := time.Parse(layout, rec["date"]); err != nil {
+			fail("date", "invalid date (expected "+layout+")")
+		} else if row.date = d.Format("2006-01-02"); row.date > today {
+			fail("date", "date is in the future")
+		}
+
+		h, ok := parseHours(rec["hours"])
+		if !ok {
+			fail("hours", "hours must be a number between 0 and 24")
+		}
+		row.hours = h
+		if row.content == "" && row.hours == 0 {
+			fail("content", "content or hours required")
+		}
+
+		switch st := models.ReportStatus(strings.ToLower(rec["status"])); st {
+		case "":
+		case models.ReportDraft, models.ReportSubmitted, models.ReportApproved:
+			row.status = st
+		default:
+			fail("status", "status must be draft, submitted or approved")
+		}
+
+		if !bad {
+			if prev, dup := seen[row.key()]; dup {
+				fail("date", "duplicate user/date (see row "+strconv.Itoa(prev)+")")
+			} else {
+				seen[row.key()] = row.line
+			}
+		}
+		if bad {
+			res.Invalid++
+			continue
+		}
+		rows = append(rows, row)
+	}
+	res.Valid = len(rows)
+
+	// mevcut raporlar: sayım ve yedek
+	existing := map[string]bson.Raw{}
+	for i := 0; i < len(rows); i += writeChunk {
+		chunk := rows[i:min(i+writeChunk, len(rows))]
+		or := make([]bson.M, len(chunk))
+		for j, row := range chunk {
+			or[j] = bson.M{"userId": row.user.ID, "date": row.date}
+		}
+		cur, err := db.Col("reports").Find(ctx, bson.M{"$or": or})
+		if err != nil {
+			return nil, err
+		}
+		for cur.Next(ctx) {
+			raw := append(bson.Raw(nil), cur.Current...)
+			existing[raw.Lookup("userId").ObjectID().Hex()+"|"+raw.Lookup("date").StringValue()] = raw
+		}
+		if err := cur.Err(); err != nil {
+			return nil, err
+		}
+		cur.Close(ctx)
+	}
+	for _, row := range rows {
+		if _, ok := existing[row.key()]; ok {
+			res.Updated++
+		} else {
+			res.Inserted++
+		}
+	}
+
+	if opt.DryRun {
+		return res, nil
+	}
+	if res.Invalid > 0 && !opt.SkipInvalid {
+		res.Inserted, res.Updated = 0, 0
+		return res, ErrInvalidRows
+	}
+
+	batch, err := newBatch(ctx, models.ImportReports, opt, res)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	for i := 0; i < len(rows); i += writeChunk {
+		chunk := rows[i:min(i+writeChunk, len(rows))]
+
+		var prev []bson.Raw
+		var revs []interface{}
+		ops := make([]mongo.WriteModel, 0, len(chunk))
+		for _, row := range chunk {
+			set := bson.M{
+				"content":       row.content,
+				"hours":         row.hours,
+				"userName":      row.user.Name,
+				"role":          row.user.Role,
+				"status":        row.status,
+				"importBatchId": batch.ID,
+			}
+			if doc, ok := existing[row.key()]; ok {
+				prev = append(prev, doc)
+				rev, err := importRevision(ctx, doc, row, opt.CreatedBy, now, set)
+				if err != nil {
+					_ = finishBatch(ctx, batch.ID, res)
+					return res, err
+				}
+				if rev != nil {
+					revs = append(revs, *rev)
+				}
+			}
+			ops = append(ops, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"userId": row.user.ID, "date": row.date}).
+				SetUpdate(bson.M{
+					"$set":         set,
+					"$setOnInsert": bson.M{"createdAt": now},
+					"$unset":       bson.M{"entries": ""},
+				}).
+				SetUpsert(true))
+		}
+		if err := backup(ctx, batch.ID, "reports", prev); err != nil {
+			return nil, err
+		}
+		if _, err := db.Col("reports").BulkWrite(ctx, ops, options.BulkWrite().SetOrdered(false)); err != nil {
+			_ = finishBatch(ctx, batch.ID, res)
+			return res, err
+		}
+		// üzerine yazılan raporların revizyonları (rapor yazıldıktan sonra, saveReport gibi)
+		if len(revs) > 0 {
+			if _, err := db.Col("report_revisions").InsertMany(ctx, revs, options.InsertMany().SetOrdered(false)); err != nil {
+				_ = finishBatch(ctx, batch.ID, res)
+				return res, err
+			}
+		}
+	}
+	return res, finishBatch(ctx, batch.ID, res)
+}
+
+// Üzerine yazılan raporun "import" revizyonunu hazırlar ve yeni rev'i set'e yazar.
+// İçerik/saat/iş kalemleri değişmiyorsa nil. Revizyon takibi öncesi raporlar için
+// önce mevcut hali baseline olarak saklanır.
+func importRevision(ctx context.Context, raw bson.Raw, row reportRow, by primitive.ObjectID, now time.Time, set bson.M) (*models.ReportRevision, error) {
+	var prev models.Report
+	if err := bson.Unmarshal(raw, &prev); err != nil {
+		return nil, err
+	}
+	if prev.Content == row.content && prev.Hours == row.hours && len(prev.Entries) == 0 {
+		return nil, nil
+	}
+	if prev.Rev == 0 {
+		if _, err := db.Col("report_revisions").InsertOne(ctx, prev.BaselineRevision()); err != nil && !mongo.IsDuplicateKeyError(err) {
+			return nil, err
+		}
+		prev.Rev = 1
+	}
+	set["rev"] = prev.Rev + 1
+	return &models.ReportRevision{
+		ReportID:     prev.ID,
+		UserID:       prev.UserID,
+		Date:         prev.Date,
+		Rev:          prev.Rev + 1,
+		Action:       models.RevisionImport,
+		Content:      row.content,
+		Hours:        row.hours,
+		PrevContent:  &prev.Content,
+		PrevHours:    &prev.Hours,
+		PrevEntries:  prev.Entries,
+		EditedBy:     by,
+		EditedByName: importEditorName,
+		EditedAt:     now,
+	}, nil
+}
@@ -0,0 +1,244 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/middleware"
+	"report-management-system/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrAlreadyRolledBack = errors.New("import batch already rolled back")
+	ErrUsersHaveReports  = errors.New("users from this batch have reports")
+)
+
+type RollbackResult struct {
+	BatchID  string `json:"batchId"`
+	Deleted  int    `json:"deleted"`  // batch'in oluşturduğu kayıtlar
+	Restored int    `json:"restored"` // üzerine yazılıp önceki haline dönen kayıtlar
+	Skipped  int    `json:"skipped"`  // sonradan değişmiş (başka import / uygulama içi düzenleme) kayıtlar
+}
+
+// Rollback bir batch'i geri alır. Sadece hâlâ bu batch'e ait (importBatchId eşleşen)
+// kayıtlara dokunulur; sonradan düzenlenenler atlanır. Kullanıcı batch'i, oluşturduğu
+// kullanıcıların raporları varken geri alınamaz (önce rapor importları geri alınmalı).
+func Rollback(ctx context.Context, batchID primitive.ObjectID, by primitive.ObjectID) (*RollbackResult, error) {
+	var batch models.ImportBatch
+	err := db.Col("import_batches").FindOne(ctx, bson.M{"_id": batchID}).Decode(&batch)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrBatchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status == models.ImportRolledBack {
+		return nil, ErrAlreadyRolledBack
+	}
+	collection := string(batch.Kind)
+	col := db.Col(collection)
+	res := &RollbackResult{BatchID: batchID.Hex()}
+
+	// yedeği olanlar üzerine yazılmış, diğerleri bu batch'te oluşturulmuş kayıtlardır
+	backed := map[primitive.ObjectID]bool{}
+	bcur, err := db.Col("import_backups").Find(ctx, bson.M{"batchId": batchID},
+		options.Find().SetProjection(bson.M{"docId": 1}))
+	if err != nil {
+		return nil, err
+	}
+	for bcur.Next(ctx) {
+		backed[bcur.Current.Lookup("docId").ObjectID()] = true
+	}
+	bcur.Close(ctx)
+
+	var created []primitive.ObjectID
+	idCur, err := col.Find(ctx, bson.M{"importBatchId": batchID}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	for idCur.Next(ctx) {
+		if id := idCur.Current.Lookup("_id").ObjectID(); !backed[id] {
+			created = append(created, id)
+		}
+	}
+	idCur.Close(ctx)
+
+	// hiçbir şeye dokunmadan önce kontrol et
+	if batch.Kind == models.ImportUsers && len(created) > 0 {
+		n, err := db.Col("reports").CountDocuments(ctx, bson.M{"userId": bson.M{"$in": created}})
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			return nil, fmt.Errorf("%w: %d report(s) belong to users created by this batch; roll back or delete them first", ErrUsersHaveReports, n)
+		}
+	}
+
+	// önceki halleri geri yükle
+	cur, err := db.Col("import_backups").Find(ctx, bson.M{"batchId": batchID})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var b struct {
+			DocID primitive.ObjectID `bson:"docId"`
+			Doc   bson.Raw           `bson:"doc"`
+		}
+		if err := cur.Decode(&b); err != nil {
+			return nil, err
+		}
+		var restored bool
+		if batch.Kind == models.ImportReports {
+			restored, err = restoreReport(ctx, batchID, b.DocID, b.Doc, by)
+		} else {
+			var r *mongo.UpdateResult
+			if r, err = col.ReplaceOne(ctx, bson.M{"_id": b.DocID, "importBatchId": batchID}, b.Doc); err == nil {
+				restored = r.MatchedCount > 0
+			}
+			// eski rolü/departmanı geri geldi
+			middleware.InvalidateUser(b.DocID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if restored {
+			res.Restored++
+		} else {
+			res.Skipped++
+		}
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(created) > 0 {
+		d, err := col.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": created}, "importBatchId": batchID})
+		if err != nil {
+			return nil, err
+		}
+		res.Deleted = int(d.DeletedCount)
+		if batch.Kind == models.ImportUsers {
+			for _, id := range created {
+				middleware.InvalidateUser(id)
+			}
+		}
+	}
+
+	now := time.Now()
+	set := bson.M{"status": models.ImportRolledBack, "rolledBackAt": now}
+	if !by.IsZero() {
+		set["rolledBackBy"] = by
+	}
+	if _, err := db.Col("import_batches").UpdateByID(ctx, batchID, bson.M{"$set": set}); err != nil {
+		return nil, err
+	}
+	_, err = db.Col("import_backups").DeleteMany(ctx, bson.M{"batchId": batchID})
+	return res, err
+}
+
+// Raporun import öncesi halini geri yükler. Geri yükleme de bir değişikliktir:
+// rev geriye gitmez, önceki hal yeni bir "restore" revizyonu olarak eklenir.
+// Rapor sonradan uygulamadan değiştiyse (düzenleme, inceleme, ek; importBatchId
+// artık eşleşmiyor) false döner.
+func restoreReport(ctx context.Context, batchID, docID primitive.ObjectID, doc bson.Raw, by primitive.ObjectID) (bool, error) {
+	var cur, prev models.Report
+	err := db.Col("reports").FindOne(ctx, bson.M{"_id": docID, "importBatchId": batchID}).Decode(&cur)
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := bson.Unmarshal(doc, &prev); err != nil {
+		return false, err
+	}
+	var repl bson.D
+	if err := bson.Unmarshal(doc, &repl); err != nil {
+		return false, err
+	}
+
+	// import iş kalemlerini siler; içerik/saat aynı ve kalem yoksa revizyon gerekmez
+	changed := cur.Content != prev.Content || cur.Hours != prev.Hours || len(prev.Entries) > 0
+	rev := cur.Rev
+	if changed {
+		if rev == 0 {
+			if _, err := db.Col("report_revisions").InsertOne(ctx, cur.BaselineRevision()); err != nil && !mongo.IsDuplicateKeyError(err) {
+				return false, err
+			}
+			rev = 1
+		}
+		rev++
+	}
+	repl = withRev(repl, rev)
+	filter := bson.M{"_id": docID, "importBatchId": batchID, "rev": cur.Rev}
+	if cur.Rev == 0 {
+		filter["rev"] = bson.M{"$exists": false}
+	}
+	if !changed {
+		r, err := db.Col("reports").ReplaceOne(ctx, filter, repl)
+		return err == nil && r.MatchedCount > 0, err
+	}
+
+	from := prev.Rev
+	if from == 0 && cur.Rev >= 2 {
+		from = 1 // import, önceki hali baseline olarak saklamıştı
+	}
+	// önce revizyon (saveReport gibi): rapor revizyonsuz ilerlemez
+	revID := primitive.NewObjectID()
+	if _, err := db.Col("report_revisions").InsertOne(ctx, models.ReportRevision{
+		ID:           revID,
+		ReportID:     docID,
+		UserID:       cur.UserID,
+		Date:         cur.Date,
+		Rev:          rev,
+		Action:       models.RevisionRestore,
+		Content:      prev.Content,
+		Hours:        prev.Hours,
+		Entries:      prev.Entries,
+		PrevContent:  &cur.Content,
+		PrevHours:    &cur.Hours,
+		RestoredFrom: from,
+		EditedBy:     by,
+		EditedByName: importEditorName,
+		EditedAt:     time.Now(),
+	}); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil // araya uygulamadan bir yazım girdi
+		}
+		return false, err
+	}
+
+	r, err := db.Col("reports").ReplaceOne(ctx, filter, repl)
+	if err == nil && r.MatchedCount > 0 {
+		return true, nil
+	}
+	// rapor geri yüklenmedi: karşılığı olmayan revizyonu geri al
+	if _, derr := db.Col("report_revisions").DeleteOne(context.WithoutCancel(ctx), bson.M{"_id": revID}); derr != nil {
+		log.Printf("report %s: restore revision %d not rolled back: %v", docID.Hex(), rev, derr)
+	}
+	return false, err
+}
+
+// Belgedeki rev alanını ayarlar (0 ise kaldırır; modelde omitempty).
+func withRev(d bson.D, rev int) bson.D {
+	out := make(bson.D, 0, len(d)+1)
+	for _, e := range d {
+		if e.Key != "rev" {
+			out = append(out, e)
+		}
+	}
+	if rev > 0 {
+		out = append(out, bson.E{Key: "rev", Value: rev})
+	}
+	return out
+}
@@ -0,0 +1,200 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/middleware"
+	"report-management-system/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Geçersiz satır varken (SkipInvalid kapalı) hiçbir şey yazılmadı.
+var ErrInvalidRows = errors.New("import has invalid rows; nothing was written")
+
+var UserFields = []string{"name", "email", "role", "department", "password"}
+
+type userRow struct {
+	line       int
+	name       string
+	email      string
+	role       models.Role
+	department *models.Department
+	password   string
+}
+
+// Kullanıcıları e-posta adresine göre ekler/günceller. Şifre sütunu boşsa yeni
+// kullanıcılar davet/şifre belirlenene kadar giriş yapamaz; mevcut şifreye dokunulmaz.
+// Superadmin hesapları içe aktarmayla oluşturulamaz ve değiştirilemez.
+func ImportUsers(ctx context.Context, r io.Reader, opt Options) (*Result, error) {
+	res := &Result{Kind: string(models.ImportUsers), DryRun: opt.DryRun, Errors: []RowError{}}
+	t, err := openTable(r, UserFields, []string{"name", "email"}, opt.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	depts := deptCache{}
+	seen := map[string]int{}
+	var rows []userRow
+	for {
+		rec, err := t.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if blankRow(rec) {
+			continue
+		}
+		res.Rows++
+
+		u := userRow{line: t.line, name: rec["name"], email: strings.ToLower(rec["email"]), password: rec["password"]}
+		bad := false
+		fail := func(field, msg string) {
+			res.addError(u.line, field, msg)
+			bad = true
+		}
+		if u.name == "" {
+			fail("name", "name required")
+		}
+		if at := strings.Index(u.email, "@"); at < 1 || at == len(u.email)-1 || strings.ContainsAny(u.email, " ,;") {
+			fail("email", "invalid email")
+		} else if prev, dup := seen[u.email]; dup {
+			fail("email", "duplicate email (see row "+strconv.Itoa(prev)+")")
+		} else {
+			seen[u.email] = u.line
+		}
+		switch role := models.Role(strings.ToLower(rec["role"])); role {
+		case "", models.RoleEmployee:
+			u.role = models.RoleEmployee
+		case models.RoleAdmin:
+			u.role = models.RoleAdmin
+		default:
+			fail("role", "role must be employee or admin")
+		}
+		if rec["department"] == "" {
+			fail("department", "department required")
+		} else if d, err := depts.resolve(ctx, rec["department"]); err != nil {
+			return nil, err
+		} else if d == nil {
+			fail("department", "unknown or archived department")
+		} else {
+			u.department = d
+		}
+
+		if bad {
+			res.Invalid++
+			continue
+		}
+		rows = append(rows, u)
+	}
+
+	// mevcut kullanıcılar: sayım + superadmin koruması
+	existing := map[string]bson.Raw{}
+	for i := 0; i < len(rows); i += writeChunk {
+		chunk := rows[i:min(i+writeChunk, len(rows))]
+		emails := make([]string, len(chunk))
+		for j, u := range chunk {
+			emails[j] = u.email
+		}
+		cur, err := db.Col("users").Find(ctx, bson.M{"email": bson.M{"$in": emails}})
+		if err != nil {
+			return nil, err
+		}
+		for cur.Next(ctx) {
+			raw := append(bson.Raw(nil), cur.Current...)
+			existing[raw.Lookup("email").StringValue()] = raw
+		}
+		if err := cur.Err(); err != nil {
+			return nil, err
+		}
+		cur.Close(ctx)
+	}
+	valid := rows[:0]
+	for _, u := range rows {
+		if doc, ok := existing[u.email]; ok {
+			if doc.Lookup("role").StringValue() == string(models.RoleSuperAdmin) {
+				res.addError(u.line, "email", "cannot overwrite a superadmin account")
+				res.Invalid++
+				continue
+			}
+			res.Updated++
+		} else {
+			res.Inserted++
+		}
+		valid = append(valid, u)
+	}
+	rows = valid
+	res.Valid = len(rows)
+
+	if opt.DryRun {
+		return res, nil
+	}
+	if res.Invalid > 0 && !opt.SkipInvalid {
+		res.Inserted, res.Updated = 0, 0
+		return res, ErrInvalidRows
+	}
+
+	batch, err := newBatch(ctx, models.ImportUsers, opt, res)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	for i := 0; i < len(rows); i += writeChunk {
+		chunk := rows[i:min(i+writeChunk, len(rows))]
+
+		var prev []bson.Raw
+		ops := make([]mongo.WriteModel, 0, len(chunk))
+		for _, u := range chunk {
+			if doc, ok := existing[u.email]; ok {
+				prev = append(prev, doc)
+			}
+			set := bson.M{
+				"name":          u.name,
+				"role":          u.role,
+				"departmentId":  u.department.ID,
+				"department":    u.department.Name,
+				"importBatchId": batch.ID,
+			}
+			if u.password != "" {
+				hash, err := bcrypt.GenerateFromPassword([]byte(u.password), 10)
+				if err != nil {
+					return nil, err
+				}
+				set["passwordHash"] = string(hash)
+			}
+			ops = append(ops, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"email": u.email}).
+				SetUpdate(bson.M{
+					"$set":         set,
+					"$setOnInsert": bson.M{"_id": primitive.NewObjectID(), "email": u.email, "createdAt": now},
+				}).
+				SetUpsert(true))
+		}
+		// yedek, üzerine yazmadan önce
+		if err := backup(ctx, batch.ID, "users", prev); err != nil {
+			return nil, err
+		}
+		_, err := db.Col("users").BulkWrite(ctx, ops, options.BulkWrite().SetOrdered(false))
+		// rolü/departmanı değişmiş olabilecek mevcut kullanıcılar (UpdateUser gibi)
+		for _, doc := range prev {
+			middleware.InvalidateUser(doc.Lookup("_id").ObjectID())
+		}
+		if err != nil {
+			_ = finishBatch(ctx, batch.ID, res)
+			return res, err
+		}
+	}
+	return res, finishBatch(ctx, batch.ID, res)
+}
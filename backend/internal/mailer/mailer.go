@@ -0,0 +1,135 @@
+package mailer
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+var ErrDisabled = errors.New("smtp not configured")
+
+// SMTP ayarları (env'den okunur). Yerel test için MailHog / smtp4dev gibi bir
+// sink kullanılabilir: SMTP_HOST=localhost SMTP_PORT=1025, kullanıcı adı boş.
+type Config struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	Timeout  time.Duration // bağlanma + gönderim için üst sınır
+}
+
+const defaultTimeout = 30 * time.Second
+
+// SMTP_HOST boşsa e-posta gönderimi kapalıdır.
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Host:     strings.TrimSpace(os.Getenv("SMTP_HOST")),
+		Port:     strings.TrimSpace(os.Getenv("SMTP_PORT")),
+		Username: strings.TrimSpace(os.Getenv("SMTP_USERNAME")),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     strings.TrimSpace(os.Getenv("SMTP_FROM")),
+	}
+	if cfg.Port == "" {
+		cfg.Port = "587"
+	}
+	if cfg.From == "" {
+		cfg.From = "reports@localhost"
+	}
+	cfg.Timeout = defaultTimeout
+	if d, err := time.ParseDuration(strings.TrimSpace(os.Getenv("SMTP_TIMEOUT"))); err == nil && d > 0 {
+		cfg.Timeout = d
+	}
+	return cfg
+}
+
+func Enabled() bool {
+	return ConfigFromEnv().Host != ""
+}
+
+// Send: düz metin e-posta. Sunucu STARTTLS sunuyorsa kullanılır; kimlik bilgisi
+// verilmişse PLAIN auth yapılır (TLS yoksa sadece localhost'a izin verilir).
+// smtp.SendMail yerine bağlantı elle kurulur: bağlanma ve tüm konuşma
+// SMTP_TIMEOUT ile sınırlıdır, takılan bir sunucu çağıran döngüyü bekletmez.
+func Send(to []string, subject, body string) error {
+	cfg := ConfigFromEnv()
+	if cfg.Host == "" {
+		return ErrDisabled
+	}
+	if len(to) == 0 {
+		return errors.New("no recipients")
+	}
+	for _, addr := range append([]string{cfg.From}, to...) {
+		if strings.ContainsAny(addr, "\r\n") {
+			return errors.New("smtp: address contains CR or LF")
+		}
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(cfg.Host, cfg.Port), cfg.Timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	// STARTTLS sonrası da alttaki bağlantının süresi geçerlidir
+	if err := conn.SetDeadline(time.Now().Add(cfg.Timeout)); err != nil {
+		return err
+	}
+	c, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if cfg.Username != "" {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(cfg.From); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(buildMessage(cfg.From, to, subject, body)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func buildMessage(from string, to []string, subject, body string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	// SMTP satır sonları CRLF olmalı
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -4,9 +4,15 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
+
+	"report-management-system/internal/db"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func JWT() gin.HandlerFunc {
@@ -33,9 +39,59 @@ func JWT() gin.HandlerFunc {
 
 		claims, _ := token.Claims.(jwt.MapClaims)
 		id, _ := claims["id"].(string)
-		role, _ := claims["role"].(string)
+		sidHex, _ := claims["sid"].(string)
+		tv, _ := claims["tv"].(float64) // JSON sayıları float64 gelir
+
+		uid, err1 := primitive.ObjectIDFromHex(id)
+		sid, err2 := primitive.ObjectIDFromHex(sidHex)
+		if err1 != nil || err2 != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		// session iptal edilmiş / süresi dolmuş mu?
+		if err := db.Col("sessions").FindOne(ctx, bson.M{
+			"_id":       sid,
+			"userId":    uid,
+			"revokedAt": bson.M{"$exists": false},
+			"expiresAt": bson.M{"$gt": time.Now().UTC()},
+		}, options.FindOne().SetProjection(bson.M{"_id": 1})).Err(); err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "session revoked"})
+			return
+		}
+
+		// rol/departman her istekte güncel kullanıcı kaydından (cache'li) çözülür;
+		// token'daki "role" claim'ine güvenilmez.
+		me, err := LoadIdentity(ctx, uid)
+		if err == ErrUserNotFound {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized", "code": "USER_NOT_FOUND"})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "user lookup failed"})
+			return
+		}
+
+		if !me.Active {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized", "code": "USER_DEACTIVATED"})
+			return
+		}
+
+		// token versiyonu güncel mi? ("sign out everywhere" sonrası eski token'lar düşer)
+		if me.TokenVersion != int(tv) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token revoked"})
+			return
+		}
+
 		c.Set("userId", id)
-		c.Set("role", role)
+		c.Set("role", string(me.Role))
+		c.Set("department", me.Department)
+		if !me.DepartmentID.IsZero() {
+			c.Set("departmentId", me.DepartmentID.Hex())
+		}
+		c.Set("sessionId", sidHex)
 		c.Next()
 	}
 }
@@ -46,8 +102,7 @@ func RequireRole(roles ...string) gin.HandlerFunc {
 		allowed[r] = struct{}{}
 	}
 	return func(c *gin.Context) {
-		role, _ := c.Get("role")
-		if _, ok := allowed[role.(string)]; !ok {
+		if _, ok := allowed[c.GetString("role")]; !ok {
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 			return
 		}
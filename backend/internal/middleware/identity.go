@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"report-management-system/internal/db"
+	"report-management-system/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrUserNotFound = errors.New("user not found")
+
+// Identity: istek sırasında geçerli olan rol/departman. JWT claim'leri yerine
+// users koleksiyonundaki güncel kayıttan çözülür.
+type Identity struct {
+	UserID       primitive.ObjectID
+	Name         string
+	Role         models.Role
+	DepartmentID primitive.ObjectID
+	Department   string
+	TokenVersion int
+	Active       bool
+}
+
+type identityEntry struct {
+	id      Identity
+	fetched time.Time
+}
+
+// Küçük, süreç içi cache. Kullanıcıyı değiştiren handler'lar InvalidateUser
+// çağırır; TTL sadece birden fazla instance çalışırken üst sınırdır.
+var identityCache = struct {
+	sync.RWMutex
+	m map[primitive.ObjectID]identityEntry
+}{m: map[primitive.ObjectID]identityEntry{}}
+
+func identityTTL() time.Duration {
+	if v := strings.TrimSpace(os.Getenv("USER_CACHE_TTL")); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			return d
+		}
+	}
+	return 30 * time.Second
+}
+
+// LoadIdentity: cache'te tazeyse oradan, değilse DB'den.
+func LoadIdentity(ctx context.Context, uid primitive.ObjectID) (Identity, error) {
+	ttl := identityTTL()
+
+	identityCache.RLock()
+	e, ok := identityCache.m[uid]
+	identityCache.RUnlock()
+	if ok && time.Since(e.fetched) < ttl {
+		return e.id, nil
+	}
+
+	var u models.User
+	err := db.Col("users").FindOne(ctx, bson.M{"_id": uid},
+		options.FindOne().SetProjection(bson.M{
+			"name": 1, "role": 1, "departmentId": 1, "department": 1, "tokenVersion": 1, "deactivatedAt": 1,
+		})).Decode(&u)
+	if err == mongo.ErrNoDocuments {
+		InvalidateUser(uid)
+		return Identity{}, ErrUserNotFound
+	}
+	if err != nil {
+		return Identity{}, err
+	}
+
+	id := Identity{
+		UserID:       u.ID,
+		Name:         u.Name,
+		Role:         u.Role,
+		DepartmentID: u.DepartmentID,
+		Department:   strings.TrimSpace(u.Department),
+		TokenVersion: u.TokenVersion,
+		Active:       u.Active(),
+	}
+	if ttl > 0 {
+		identityCache.Lock()
+		identityCache.m[uid] = identityEntry{id: id, fetched: time.Now()}
+		identityCache.Unlock()
+	}
+	return id, nil
+}
+
+// Kullanıcının rolü/departmanı/token versiyonu değiştiğinde ya da silindiğinde çağır.
+func InvalidateUser(uid primitive.ObjectID) {
+	identityCache.Lock()
+	delete(identityCache.m, uid)
+	identityCache.Unlock()
+}
+
+// Toplu değişikliklerde (örn. departman rename) tüm cache'i boşalt.
+func InvalidateAllUsers() {
+	identityCache.Lock()
+	identityCache.m = map[primitive.ObjectID]identityEntry{}
+	identityCache.Unlock()
+}
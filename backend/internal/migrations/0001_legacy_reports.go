@@ -0,0 +1,192 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"report-management-system/internal/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Eski rapor dokümanları kullanıcıyı userId/uid/user_id alanlarında, ObjectID
+// ya da string olarak tutuyordu; bazılarında date time.Time, hours string idi.
+// Bu migration hepsini models.Report şekline getirir:
+//   - userId: ObjectID, uid/user_id silinir
+//   - date: "YYYY-MM-DD" string
+//   - hours: 0–24 arası sayı
+//   - createdAt: yoksa _id zaman damgası
+//
+// Aynı (userId, date) için kanonik bir rapor zaten varsa eski doküman ona
+// birleştirilir: farklı içerikler alt alta eklenir, saat olarak büyük olan,
+// createdAt olarak erken olan alınır; eski doküman silinir.
+func normalizeLegacyReports(ctx context.Context) (string, error) {
+	reports := db.Col("reports")
+
+	legacy := bson.M{"$or": []bson.M{
+		{"userId": bson.M{"$exists": false}},
+		{"userId": bson.M{"$type": "string"}},
+		{"uid": bson.M{"$exists": true}},
+		{"user_id": bson.M{"$exists": true}},
+		{"date": bson.M{"$not": bson.M{"$type": "string"}}},
+		{"hours": bson.M{"$type": "string"}},
+		{"createdAt": bson.M{"$exists": false}},
+	}}
+
+	cur, err := reports.Find(ctx, legacy)
+	if err != nil {
+		return "", err
+	}
+	defer cur.Close(ctx)
+
+	var normalized, merged, skipped int
+	for cur.Next(ctx) {
+		var doc bson.M
+		if err := cur.Decode(&doc); err != nil {
+			return "", err
+		}
+		id, _ := doc["_id"].(primitive.ObjectID)
+
+		uid, ok := legacyUserID(doc)
+		date, okDate := legacyDate(doc["date"])
+		if !ok || !okDate {
+			skipped++
+			continue
+		}
+		content, _ := doc["content"].(string)
+		hours := clampHours(legacyHours(doc["hours"]))
+		created, ok := doc["createdAt"].(primitive.DateTime)
+		createdAt := created.Time()
+		if !ok {
+			createdAt = id.Timestamp()
+		}
+
+		// aynı kullanıcı/gün için kanonik kayıt var mı?
+		var canon bson.M
+		err := reports.FindOne(ctx, bson.M{
+			"userId": uid,
+			"date":   date,
+			"_id":    bson.M{"$ne": id},
+		}).Decode(&canon)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return "", err
+		}
+
+		if err == nil {
+			if err := mergeInto(ctx, reports, canon, content, hours, createdAt); err != nil {
+				return "", err
+			}
+			if _, err := reports.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+				return "", err
+			}
+			merged++
+			continue
+		}
+
+		_, err = reports.UpdateByID(ctx, id, bson.M{
+			"$set": bson.M{
+				"userId":    uid,
+				"date":      date,
+				"content":   strings.TrimSpace(content),
+				"hours":     hours,
+				"createdAt": createdAt,
+			},
+			"$unset": bson.M{"uid": "", "user_id": ""},
+		})
+		if err != nil {
+			return "", err
+		}
+		normalized++
+	}
+	if err := cur.Err(); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("normalized %d, merged %d duplicates, skipped %d unresolvable", normalized, merged, skipped), nil
+}
+
+func mergeInto(ctx context.Context, reports *mongo.Collection, canon bson.M, content string, hours float64, createdAt time.Time) error {
+	set := bson.M{}
+
+	cur, _ := canon["content"].(string)
+	content = strings.TrimSpace(content)
+	if content != "" && !strings.Contains(cur, content) {
+		if strings.TrimSpace(cur) == "" {
+			set["content"] = content
+		} else {
+			set["content"] = strings.TrimSpace(cur) + "\n\n" + content
+		}
+	}
+	if hours > clampHours(legacyHours(canon["hours"])) {
+		set["hours"] = hours
+	}
+	if ct, ok := canon["createdAt"].(primitive.DateTime); !ok || createdAt.Before(ct.Time()) {
+		set["createdAt"] = createdAt
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	_, err := reports.UpdateByID(ctx, canon["_id"], bson.M{"$set": set})
+	return err
+}
+
+func legacyUserID(doc bson.M) (primitive.ObjectID, bool) {
+	for _, k := range []string{"userId", "uid", "user_id"} {
+		switch v := doc[k].(type) {
+		case primitive.ObjectID:
+			if !v.IsZero() {
+				return v, true
+			}
+		case string:
+			if oid, err := primitive.ObjectIDFromHex(strings.TrimSpace(v)); err == nil {
+				return oid, true
+			}
+		}
+	}
+	return primitive.NilObjectID, false
+}
+
+func legacyDate(v interface{}) (string, bool) {
+	switch d := v.(type) {
+	case string:
+		s := strings.TrimSpace(d)
+		if len(s) >= 10 {
+			if _, err := time.Parse("2006-01-02", s[:10]); err == nil {
+				return s[:10], true
+			}
+		}
+	case primitive.DateTime:
+		return d.Time().UTC().Format("2006-01-02"), true
+	}
+	return "", false
+}
+
+func legacyHours(v interface{}) float64 {
+	switch h := v.(type) {
+	case float64:
+		return h
+	case int32:
+		return float64(h)
+	case int64:
+		return float64(h)
+	case string:
+		f, _ := strconv.ParseFloat(strings.TrimSpace(strings.Replace(h, ",", ".", 1)), 64)
+		return f
+	}
+	return 0
+}
+
+func clampHours(h float64) float64 {
+	if h < 0 {
+		return 0
+	}
+	if h > 24 {
+		return 24
+	}
+	return h
+}
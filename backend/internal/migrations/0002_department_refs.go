@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"report-management-system/internal/db"
+)
+
+// Serbest metin departman adlarını departments._id'ye bağlar (bkz. db.MigrateDepartmentRefs).
+// Eşleşmeyen değerler notlara yazılır; departman oluşturulup
+// `migrate refs` komutuyla tekrar eşlenebilir.
+func departmentRefs(ctx context.Context) (string, error) {
+	rep, err := db.MigrateDepartmentRefs(ctx)
+	if err != nil {
+		return "", err
+	}
+	notes := fmt.Sprintf("mapped %d users, %d reminders, %d invites",
+		rep.UsersMapped, rep.RemindersMapped, rep.InvitesMapped)
+	if len(rep.Unmatched) > 0 {
+		parts := make([]string, 0, len(rep.Unmatched))
+		for _, u := range rep.Unmatched {
+			parts = append(parts, fmt.Sprintf("%s:%q(%d)", u.Collection, u.Value, u.Count))
+		}
+		notes += "; unmatched: " + strings.Join(parts, ", ")
+	}
+	return notes, nil
+}
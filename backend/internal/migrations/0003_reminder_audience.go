@@ -0,0 +1,62 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"report-management-system/internal/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoDB hata kodları: koleksiyon ya da index yok
+const (
+	namespaceNotFound = 26
+	indexNotFound     = 27
+)
+
+// Tek departmanlı eski hatırlatmaları hedef kitle listelerine taşır
+// (targetDepartmentId -> targetDepartmentIds, targetDepartment -> targetDepartments)
+// ve kişisel otomatik hatırlatmaların targetUserId alanını targetUserIds'e çevirir.
+// Eski alanlar (targetUserId hariç) eski istemciler için korunur; targetUserId'nin
+// kısmi indexi (target_user_created) kaldırılır.
+func reminderAudience(ctx context.Context) (string, error) {
+	reminders := db.Col("reminders")
+
+	deps, err := reminders.UpdateMany(ctx,
+		bson.M{
+			"targetDepartmentId":  bson.M{"$exists": true},
+			"targetDepartmentIds": bson.M{"$exists": false},
+		},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{
+				"targetDepartmentIds": bson.A{"$targetDepartmentId"},
+				"targetDepartments":   bson.A{"$targetDepartment"},
+			}}},
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	users, err := reminders.UpdateMany(ctx,
+		bson.M{"targetUserId": bson.M{"$exists": true}},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{"targetUserIds": bson.A{"$targetUserId"}}}},
+			{{Key: "$unset", Value: "targetUserId"}},
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	// index (ya da koleksiyon) hiç oluşturulmamış olabilir
+	var cmdErr mongo.CommandError
+	if _, err := reminders.Indexes().DropOne(ctx, "target_user_created"); err != nil &&
+		!(errors.As(err, &cmdErr) && (cmdErr.Code == indexNotFound || cmdErr.Code == namespaceNotFound)) {
+		return "", err
+	}
+	return fmt.Sprintf("department targets %d, user targets %d", deps.ModifiedCount, users.ModifiedCount), nil
+}
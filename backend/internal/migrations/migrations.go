@@ -0,0 +1,174 @@
+// Package migrations: sürümlü şema migration'ları. Uygulananlar
+// schema_migrations koleksiyonunda tutulur; her sürüm bir kez çalışır.
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"report-management-system/internal/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	collection = "schema_migrations"
+	lockID     = "lock"
+	// çöken bir instance'ın bıraktığı kilit bu süreden sonra devralınabilir
+	staleLockAfter = 15 * time.Minute
+)
+
+// Migration: Up idempotent olmalı; dönen string kayda "notes" olarak yazılır.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context) (string, error)
+}
+
+// Sıralı kayıt listesi. Yeni migration eklerken sadece sona ekleyin,
+// mevcut sürüm numaralarını değiştirmeyin.
+var registry = []Migration{
+	{Version: 1, Name: "normalize_legacy_reports", Up: normalizeLegacyReports},
+	{Version: 2, Name: "department_refs", Up: departmentRefs},
+	{Version: 3, Name: "reminder_audience", Up: reminderAudience},
+}
+
+type Record struct {
+	Version    int       `bson:"version"    json:"version"`
+	Name       string    `bson:"name"       json:"name"`
+	AppliedAt  time.Time `bson:"appliedAt"  json:"appliedAt"`
+	DurationMs int64     `bson:"durationMs" json:"durationMs"`
+	Notes      string    `bson:"notes"      json:"notes,omitempty"`
+}
+
+type Status struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+	Notes     string     `json:"notes,omitempty"`
+}
+
+func col() *mongo.Collection { return db.Col(collection) }
+
+func ensureIndexes(ctx context.Context) error {
+	_, err := col().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "version", Value: 1}},
+		Options: options.Index().
+			SetName("uniq_version").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"version": bson.M{"$exists": true}}),
+	})
+	return err
+}
+
+func applied(ctx context.Context) (map[int]Record, error) {
+	cur, err := col().Find(ctx, bson.M{"version": bson.M{"$exists": true}})
+	if err != nil {
+		return nil, err
+	}
+	var recs []Record
+	if err := cur.All(ctx, &recs); err != nil {
+		return nil, err
+	}
+	out := make(map[int]Record, len(recs))
+	for _, r := range recs {
+		out[r.Version] = r
+	}
+	return out, nil
+}
+
+// Birden çok instance aynı anda başlarsa sadece biri migration çalıştırır.
+func acquireLock(ctx context.Context) (bool, error) {
+	now := time.Now().UTC()
+	_, err := col().UpdateOne(ctx,
+		bson.M{"_id": lockID, "lockedAt": bson.M{"$lt": now.Add(-staleLockAfter)}},
+		bson.M{"$set": bson.M{"lockedAt": now}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil // kilit başka bir instance'ta
+	}
+	return err == nil, err
+}
+
+func releaseLock(ctx context.Context) {
+	_, _ = col().DeleteOne(ctx, bson.M{"_id": lockID})
+}
+
+// Run: bekleyen migration'ları sürüm sırasıyla uygular ve uygulananları döner.
+func Run(ctx context.Context) ([]Record, error) {
+	if db.Col(collection) == nil {
+		return nil, fmt.Errorf("migrations: database not selected")
+	}
+	if err := ensureIndexes(ctx); err != nil {
+		return nil, err
+	}
+
+	ok, err := acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		log.Println("migrations: another instance holds the lock, skipping")
+		return nil, nil
+	}
+	defer releaseLock(ctx)
+
+	done, err := applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := append([]Migration(nil), registry...)
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+
+	var out []Record
+	for _, m := range list {
+		if _, ok := done[m.Version]; ok {
+			continue
+		}
+		start := time.Now()
+		notes, err := m.Up(ctx)
+		if err != nil {
+			return out, fmt.Errorf("migration %d (%s): %w", m.Version, m.Name, err)
+		}
+		rec := Record{
+			Version:    m.Version,
+			Name:       m.Name,
+			AppliedAt:  time.Now().UTC(),
+			DurationMs: time.Since(start).Milliseconds(),
+			Notes:      notes,
+		}
+		if _, err := col().InsertOne(ctx, rec); err != nil {
+			return out, err
+		}
+		log.Printf("migrations: applied %d (%s) in %dms: %s", rec.Version, rec.Name, rec.DurationMs, rec.Notes)
+		out = append(out, rec)
+	}
+	return out, nil
+}
+
+// List: kayıtlı tüm migration'lar ve uygulanma durumları.
+func List(ctx context.Context) ([]Status, error) {
+	done, err := applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Status, 0, len(registry))
+	for _, m := range registry {
+		st := Status{Version: m.Version, Name: m.Name}
+		if r, ok := done[m.Version]; ok {
+			at := r.AppliedAt
+			st.Applied, st.AppliedAt, st.Notes = true, &at, r.Notes
+		}
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Rapor yazmayanlar için otomatik hatırlatma takvimi. DepartmentID boşsa şirket
+// varsayılanıdır; kendi kaydı olmayan departmanlar varsayılanı kullanır.
+type AutoReminderSchedule struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty"          json:"id"`
+	DepartmentID *primitive.ObjectID `bson:"departmentId,omitempty" json:"departmentId,omitempty"`
+	Department   string              `bson:"department,omitempty"   json:"department,omitempty"`
+	Enabled      bool                `bson:"enabled"                json:"enabled"`
+
+	// Yerel saat, "HH:MM". Cutoff'ta raporu olmayanlara hatırlatma gider;
+	// EscalateAt (opsiyonel, cutoff'tan sonra) hâlâ eksik olanlar admin'e özetlenir.
+	Cutoff     string `bson:"cutoff"               json:"cutoff"`
+	EscalateAt string `bson:"escalateAt,omitempty" json:"escalateAt,omitempty"`
+
+	Email         bool   `bson:"email"             json:"email"`         // çalışana e-posta da gönder
+	EscalateEmail bool   `bson:"escalateEmail"     json:"escalateEmail"` // admin özeti e-postayla da
+	Message       string `bson:"message,omitempty" json:"message,omitempty"`
+
+	UpdatedBy primitive.ObjectID `bson:"updatedBy" json:"updatedBy"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+type AutoReminderKind string
+
+const (
+	AutoReminderNotice     AutoReminderKind = "reminder"
+	AutoReminderEscalation AutoReminderKind = "escalation"
+)
+
+type AutoReminderRunStatus string
+
+const (
+	AutoRunRunning   AutoReminderRunStatus = "running"
+	AutoRunCompleted AutoReminderRunStatus = "completed"
+	AutoRunFailed    AutoReminderRunStatus = "failed"
+)
+
+// Tek bir (departman, gün, tür) çalıştırması. (departmentId, date, kind) tekildir;
+// çalıştırma LeaseUntil ile sahiplenilir. Yarıda kalan (çöken) ya da hata veren
+// çalıştırma kira bitince yeniden alınır; hatırlatma/bildirimler kullanıcı başına
+// tekil olduğundan tekrar çalıştırmak sadece eksik kalanları gönderir.
+type AutoReminderRun struct {
+	ID           primitive.ObjectID    `bson:"_id,omitempty"  json:"id"`
+	DepartmentID primitive.ObjectID    `bson:"departmentId"   json:"departmentId"`
+	Department   string                `bson:"department"     json:"department"`
+	Date         string                `bson:"date"           json:"date"` // YYYY-MM-DD
+	Kind         AutoReminderKind      `bson:"kind"           json:"kind"`
+	Status       AutoReminderRunStatus `bson:"status"         json:"status"`
+
+	MissingUsers []primitive.ObjectID `bson:"missingUsers,omitempty" json:"missingUsers,omitempty"`
+	Reminders    int                  `bson:"reminders"              json:"reminders"` // oluşturulan hatırlatma
+	Notified     int                  `bson:"notified"               json:"notified"`  // bildirim alan admin
+	Emailed      int                  `bson:"emailed"                json:"emailed"`
+	EmailErrors  int                  `bson:"emailErrors"            json:"emailErrors"`
+	Error        string               `bson:"error,omitempty"        json:"error,omitempty"`
+	Attempts     int                  `bson:"attempts"               json:"attempts"`
+	LeaseUntil   *time.Time           `bson:"leaseUntil,omitempty"   json:"leaseUntil,omitempty"` // running: sahiplenme, failed: sonraki deneme
+
+	StartedAt  time.Time  `bson:"startedAt"            json:"startedAt"`
+	FinishedAt *time.Time `bson:"finishedAt,omitempty" json:"finishedAt,omitempty"`
+}
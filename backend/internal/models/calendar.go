@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Departmanın çalışma günleri verilmemişse: Pazartesi-Cuma (time.Weekday, 0 = Pazar)
+var DefaultWorkDays = []int{1, 2, 3, 4, 5}
+
+// Resmi tatil / şirket tatili. DepartmentID boşsa tüm şirket için geçerlidir.
+type Holiday struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty"          json:"id"`
+	Date         string              `bson:"date"                   json:"date"` // YYYY-MM-DD
+	Name         string              `bson:"name"                   json:"name"`
+	DepartmentID *primitive.ObjectID `bson:"departmentId,omitempty" json:"departmentId,omitempty"`
+	CreatedBy    primitive.ObjectID  `bson:"createdBy"              json:"createdBy"`
+	CreatedAt    time.Time           `bson:"createdAt"              json:"createdAt"`
+}
+
+type LeaveType string
+
+const (
+	LeaveVacation LeaveType = "vacation"
+	LeaveSick     LeaveType = "sick"
+	LeaveOther    LeaveType = "other"
+)
+
+// Kullanıcı izni; From ve To dahil (YYYY-MM-DD).
+type Leave struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"          json:"id"`
+	UserID       primitive.ObjectID `bson:"userId"                 json:"userId"`
+	UserName     string             `bson:"userName,omitempty"     json:"userName,omitempty"`
+	DepartmentID primitive.ObjectID `bson:"departmentId,omitempty" json:"departmentId,omitempty"`
+	From         string             `bson:"from"                   json:"from"`
+	To           string             `bson:"to"                     json:"to"`
+	Type         LeaveType          `bson:"type"                   json:"type"`
+	Note         string             `bson:"note,omitempty"         json:"note,omitempty"`
+	CreatedBy    primitive.ObjectID `bson:"createdBy"              json:"createdBy"`
+	CreatedAt    time.Time          `bson:"createdAt"              json:"createdAt"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Rapor yorumu. ParentID doluysa bir yoruma cevaptır.
+// Silinen yorum cevapları kopmasın diye yumuşak silinir (DeletedAt, Body boşaltılır).
+type ReportComment struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty"      json:"id"`
+	ReportID      primitive.ObjectID  `bson:"reportId"           json:"reportId"`
+	ReportOwnerID primitive.ObjectID  `bson:"reportOwnerId"      json:"reportOwnerId"`
+	ParentID      *primitive.ObjectID `bson:"parentId,omitempty" json:"parentId,omitempty"`
+
+	AuthorID   primitive.ObjectID `bson:"authorId"   json:"authorId"`
+	AuthorName string             `bson:"authorName" json:"authorName"`
+	AuthorRole Role               `bson:"authorRole" json:"authorRole"`
+
+	Body      string     `bson:"body"                json:"body"`
+	CreatedAt time.Time  `bson:"createdAt"           json:"createdAt"`
+	EditedAt  *time.Time `bson:"editedAt,omitempty"  json:"editedAt,omitempty"`
+	DeletedAt *time.Time `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+}
+
+// Kullanıcının bir raporun yorumlarını en son ne zaman okuduğu (okunmamış sayısı için).
+type CommentRead struct {
+	UserID     primitive.ObjectID `bson:"userId"`
+	ReportID   primitive.ObjectID `bson:"reportId"`
+	LastReadAt time.Time          `bson:"lastReadAt"`
+}
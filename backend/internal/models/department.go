@@ -7,8 +7,18 @@ import (
 )
 
 type Department struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name      string             `bson:"name"         json:"name"`
-	Active    bool               `bson:"active"       json:"active"`
-	CreatedAt time.Time          `bson:"createdAt"    json:"createdAt"`
+	ID         primitive.ObjectID `bson:"_id,omitempty"        json:"id"`
+	Name       string             `bson:"name"                 json:"name"`
+	Active     bool               `bson:"active"               json:"active"`
+	CreatedAt  time.Time          `bson:"createdAt"            json:"createdAt"`
+	ArchivedAt *time.Time         `bson:"archivedAt,omitempty" json:"archivedAt,omitempty"`
+	WorkDays   []int              `bson:"workDays,omitempty"   json:"workDays,omitempty"` // time.Weekday; boşsa DefaultWorkDays
+}
+
+// Departmanın çalışma günleri (0 = Pazar ... 6 = Cumartesi).
+func (d Department) Workweek() []int {
+	if len(d.WorkDays) == 0 {
+		return DefaultWorkDays
+	}
+	return d.WorkDays
 }
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ImportKind string
+
+const (
+	ImportUsers   ImportKind = "users"
+	ImportReports ImportKind = "reports"
+)
+
+type ImportStatus string
+
+const (
+	ImportCompleted  ImportStatus = "completed"
+	ImportRolledBack ImportStatus = "rolled_back"
+)
+
+// CSV içe aktarma işi. İçe aktarılan kayıtlar importBatchId ile işaretlenir;
+// üzerine yazılan mevcut kayıtların önceki hali import_backups'ta saklanır.
+type ImportBatch struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Kind      ImportKind         `bson:"kind"          json:"kind"`
+	Source    string             `bson:"source"        json:"source"` // dosya adı
+	Status    ImportStatus       `bson:"status"        json:"status"`
+	CreatedBy primitive.ObjectID `bson:"createdBy,omitempty" json:"createdBy,omitempty"` // CLI'dan ise boş
+	CreatedAt time.Time          `bson:"createdAt"     json:"createdAt"`
+
+	Rows     int `bson:"rows"     json:"rows"`
+	Inserted int `bson:"inserted" json:"inserted"`
+	Updated  int `bson:"updated"  json:"updated"`
+	Skipped  int `bson:"skipped"  json:"skipped"` // geçersiz satırlar (skipInvalid ile)
+
+	RolledBackAt *time.Time          `bson:"rolledBackAt,omitempty" json:"rolledBackAt,omitempty"`
+	RolledBackBy *primitive.ObjectID `bson:"rolledBackBy,omitempty" json:"rolledBackBy,omitempty"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Davet: rol ve departman davet eden tarafından sabitlenir, tek kullanımlık.
+// Ham token sadece oluşturulduğunda döner; DB'de sha256 hash'i tutulur.
+type Invite struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"          json:"id"`
+	TokenHash    string             `bson:"tokenHash"              json:"-"`
+	Email        string             `bson:"email,omitempty"        json:"email,omitempty"` // opsiyonel: sadece bu e-posta kullanabilir
+	Role         Role               `bson:"role"                   json:"role"`
+	DepartmentID primitive.ObjectID `bson:"departmentId,omitempty" json:"departmentId,omitempty"`
+	Department   string             `bson:"department,omitempty"   json:"department,omitempty"`
+
+	CreatedBy     primitive.ObjectID `bson:"createdBy"     json:"createdBy"`
+	CreatedByName string             `bson:"createdByName" json:"createdByName"`
+	CreatedAt     time.Time          `bson:"createdAt"     json:"createdAt"`
+	ExpiresAt     time.Time          `bson:"expiresAt"     json:"expiresAt"`
+
+	UsedAt    *time.Time          `bson:"usedAt,omitempty"    json:"usedAt,omitempty"`
+	UsedBy    *primitive.ObjectID `bson:"usedBy,omitempty"    json:"usedBy,omitempty"`
+	UsedEmail string              `bson:"usedEmail,omitempty" json:"usedEmail,omitempty"`
+	RevokedAt *time.Time          `bson:"revokedAt,omitempty" json:"revokedAt,omitempty"`
+}
+
+// pending | used | revoked | expired
+func (i Invite) Status(now time.Time) string {
+	switch {
+	case i.UsedAt != nil:
+		return "used"
+	case i.RevokedAt != nil:
+		return "revoked"
+	case !i.ExpiresAt.After(now):
+		return "expired"
+	default:
+		return "pending"
+	}
+}
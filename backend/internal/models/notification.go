@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type NotificationType string
+
+const (
+	NotificationSearchDigest   NotificationType = "search_digest"
+	NotificationMissingReports NotificationType = "missing_reports" // otomatik hatırlatma eskalasyonu
+)
+
+// Kullanıcıya özel uygulama içi bildirim (reminder'lardan farklı olarak tek alıcılı).
+type Notification struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID primitive.ObjectID `bson:"userId"        json:"userId"`
+	Type   NotificationType   `bson:"type"          json:"type"`
+	Title  string             `bson:"title"         json:"title"`
+	Body   string             `bson:"body"          json:"body"`
+
+	// Bildirimi doğuran kayıt (ör. kayıtlı arama) ve ilgili raporlar
+	SourceID  *primitive.ObjectID  `bson:"sourceId,omitempty"  json:"sourceId,omitempty"`
+	ReportIDs []primitive.ObjectID `bson:"reportIds,omitempty" json:"reportIds,omitempty"`
+
+	ReadAt    *time.Time `bson:"readAt,omitempty" json:"readAt,omitempty"`
+	CreatedAt time.Time  `bson:"createdAt"        json:"createdAt"`
+}
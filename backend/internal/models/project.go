@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Faturalanan iş birimi. Rapor iş kalemleri projectId ile referans verir.
+type Project struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"          json:"id"`
+	Name         string             `bson:"name"                   json:"name"`
+	Code         string             `bson:"code"                   json:"code"` // büyük harf, benzersiz (örn. "ACME-WEB")
+	DepartmentID primitive.ObjectID `bson:"departmentId"           json:"departmentId"`
+	Department   string             `bson:"department,omitempty"   json:"department,omitempty"` // denormalize
+	Active       bool               `bson:"active"                 json:"active"`
+	BudgetHours  *float64           `bson:"budgetHours,omitempty"  json:"budgetHours,omitempty"`
+	CreatedBy    primitive.ObjectID `bson:"createdBy"              json:"createdBy"`
+	CreatedAt    time.Time          `bson:"createdAt"              json:"createdAt"`
+	UpdatedAt    *time.Time         `bson:"updatedAt,omitempty"    json:"updatedAt,omitempty"`
+}
@@ -15,16 +15,28 @@ const (
 	ReminderError   ReminderType = "error"
 )
 
+// Hedef kitle birleşimdir (OR): "all", departmanlardan biri, rollerden biri ya da
+// listedeki kullanıcılardan biri olan herkes görür.
 type Reminder struct {
-	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Content          string             `bson:"content" json:"content"`
-	Type             ReminderType       `bson:"type" json:"type"`                         // info|warning|success|error
-	TargetDepartment string             `bson:"targetDepartment" json:"targetDepartment"` // "all" veya departman adı
-	SenderID         primitive.ObjectID `bson:"senderId" json:"senderId"`
-	SenderName       string             `bson:"senderName" json:"senderName"`
-	SenderRole       Role               `bson:"senderRole" json:"senderRole"`
-	Duration         string             `bson:"duration" json:"duration"` // temporary|permanent
-	IsActive         bool               `bson:"isActive" json:"isActive"`
-	ExpiresAt        *time.Time         `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"`
-	CreatedAt        time.Time          `bson:"createdAt" json:"createdAt"`
+	ID                 primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Content            string              `bson:"content" json:"content"`
+	Type               ReminderType        `bson:"type" json:"type"`                                                 // info|warning|success|error
+	TargetDepartment   string              `bson:"targetDepartment" json:"targetDepartment"`                         // "all", tek departman adı (denormalize) veya "" (liste hedefleri)
+	TargetDepartmentID *primitive.ObjectID `bson:"targetDepartmentId,omitempty" json:"targetDepartmentId,omitempty"` // sadece tek departmanlık hedefte (eski istemciler)
+
+	TargetDepartmentIDs []primitive.ObjectID `bson:"targetDepartmentIds,omitempty" json:"targetDepartmentIds,omitempty"`
+	TargetDepartments   []string             `bson:"targetDepartments,omitempty" json:"targetDepartments,omitempty"` // adlar (denormalize)
+	TargetRoles         []Role               `bson:"targetRoles,omitempty" json:"targetRoles,omitempty"`
+	TargetUserIDs       []primitive.ObjectID `bson:"targetUserIds,omitempty" json:"targetUserIds,omitempty"`
+
+	AutoRunID    *primitive.ObjectID `bson:"autoRunId,omitempty" json:"autoRunId,omitempty"`       // otomatik hatırlatma çalıştırması
+	SeriesID     *primitive.ObjectID `bson:"seriesId,omitempty" json:"seriesId,omitempty"`         // zamanlanmış/tekrarlı seriden üretildi
+	OccurrenceAt *time.Time          `bson:"occurrenceAt,omitempty" json:"occurrenceAt,omitempty"` // serideki yayın zamanı
+	SenderID     primitive.ObjectID  `bson:"senderId" json:"senderId"`
+	SenderName   string              `bson:"senderName" json:"senderName"`
+	SenderRole   Role                `bson:"senderRole" json:"senderRole"`
+	Duration     string              `bson:"duration" json:"duration"` // temporary|permanent|custom (expiresAt elle verildi)
+	IsActive     bool                `bson:"isActive" json:"isActive"`
+	ExpiresAt    *time.Time          `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"`
+	CreatedAt    time.Time           `bson:"createdAt" json:"createdAt"`
 }
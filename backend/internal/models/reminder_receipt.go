@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Hatırlatmanın bir kullanıcıdaki durumu: iletildi (listede gösterildi),
+// okundu, onaylandı, kapatıldı. Her alan ilk gerçekleştiği zamanı tutar.
+type ReminderReceipt struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"            json:"id"`
+	ReminderID     primitive.ObjectID `bson:"reminderId"               json:"reminderId"`
+	UserID         primitive.ObjectID `bson:"userId"                   json:"userId"`
+	DeliveredAt    *time.Time         `bson:"deliveredAt,omitempty"    json:"deliveredAt,omitempty"`
+	ReadAt         *time.Time         `bson:"readAt,omitempty"         json:"readAt,omitempty"`
+	AcknowledgedAt *time.Time         `bson:"acknowledgedAt,omitempty" json:"acknowledgedAt,omitempty"`
+	DismissedAt    *time.Time         `bson:"dismissedAt,omitempty"    json:"dismissedAt,omitempty"` // listede gizlenir
+}
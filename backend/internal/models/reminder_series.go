@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ReminderSeriesStatus string
+
+const (
+	ReminderSeriesScheduled ReminderSeriesStatus = "scheduled"
+	ReminderSeriesCompleted ReminderSeriesStatus = "completed"
+	ReminderSeriesCancelled ReminderSeriesStatus = "cancelled"
+)
+
+// İleri tarihli ya da tekrarlı hatırlatma. Zamanlayıcı her tekrarda Template'in
+// bir kopyasını "reminders" koleksiyonuna düz bir hatırlatma olarak yazar.
+type ReminderSeries struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Template Reminder           `bson:"template"      json:"template"` // içerik, tür, hedef kitle, gönderen
+
+	RRule   string    `bson:"rrule,omitempty" json:"rrule,omitempty"` // boşsa tek seferlik (StartAt'ta)
+	StartAt time.Time `bson:"startAt"         json:"startAt"`         // ilk yayın / tekrar başlangıcı
+
+	// Bitiş: tek seferlikte ExpiresAt, tekrarlıda her tekrar için ExpiresAfter ("24h" gibi).
+	// İkisi de boşsa kalıcı.
+	ExpiresAt    *time.Time `bson:"expiresAt,omitempty"    json:"expiresAt,omitempty"`
+	ExpiresAfter string     `bson:"expiresAfter,omitempty" json:"expiresAfter,omitempty"`
+
+	Status         ReminderSeriesStatus `bson:"status"                   json:"status"`
+	NextRunAt      *time.Time           `bson:"nextRunAt,omitempty"      json:"nextRunAt,omitempty"`  // sıradaki gerçek tekrar zamanı
+	LeaseUntil     *time.Time           `bson:"leaseUntil,omitempty"     json:"leaseUntil,omitempty"` // zamanlayıcı sahiplenmesi (bitince yeniden denenir)
+	Occurrences    int                  `bson:"occurrences"              json:"occurrences"`          // işlenen tekrar (COUNT için)
+	Published      int                  `bson:"published"                json:"published"`            // yazılan hatırlatma
+	LastRunAt      *time.Time           `bson:"lastRunAt,omitempty"      json:"lastRunAt,omitempty"`
+	LastReminderID *primitive.ObjectID  `bson:"lastReminderId,omitempty" json:"lastReminderId,omitempty"`
+	LastError      string               `bson:"lastError,omitempty"      json:"lastError,omitempty"`
+
+	CreatedAt   time.Time  `bson:"createdAt"             json:"createdAt"`
+	CancelledAt *time.Time `bson:"cancelledAt,omitempty" json:"cancelledAt,omitempty"`
+}
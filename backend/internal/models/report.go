@@ -18,4 +18,74 @@ type Report struct {
 	Content   string    `bson:"content"          json:"content"`
 	Hours     float64   `bson:"hours,omitempty"   json:"hours,omitempty"`
 	CreatedAt time.Time `bson:"createdAt"         json:"createdAt"`
+
+	// Opsiyonel iş kalemleri; varsa Hours bunların toplamıdır.
+	// Eski (tek içerikli) raporlarda boş.
+	Entries []ReportEntry `bson:"entries,omitempty" json:"entries,omitempty"`
+
+	// Ek dosyalar (içerik storage'da; burada sadece metadata)
+	Attachments []Attachment `bson:"attachments,omitempty" json:"attachments,omitempty"`
+
+	// Onay akışı; eski kayıtlarda boş (= submitted kabul edilir)
+	Status         ReportStatus        `bson:"status,omitempty"         json:"status,omitempty"`
+	SubmittedAt    *time.Time          `bson:"submittedAt,omitempty"    json:"submittedAt,omitempty"`
+	ReviewedBy     *primitive.ObjectID `bson:"reviewedBy,omitempty"     json:"reviewedBy,omitempty"`
+	ReviewedByName string              `bson:"reviewedByName,omitempty" json:"reviewedByName,omitempty"`
+	ReviewedAt     *time.Time          `bson:"reviewedAt,omitempty"     json:"reviewedAt,omitempty"`
+	ReviewComment  string              `bson:"reviewComment,omitempty"  json:"reviewComment,omitempty"`
+
+	// Son revizyon numarası (report_revisions.rev); eski kayıtlarda 0
+	Rev int `bson:"rev,omitempty" json:"rev,omitempty"`
+
+	// Son düzenleme (kullanıcının kendisi veya admin override)
+	UpdatedAt *time.Time          `bson:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+	UpdatedBy *primitive.ObjectID `bson:"updatedBy,omitempty" json:"updatedBy,omitempty"`
+
+	// CSV import ile geldiyse batch ID'si (geri alma için; uygulamadan düzenlenince silinir)
+	ImportBatchID *primitive.ObjectID `bson:"importBatchId,omitempty" json:"importBatchId,omitempty"`
+}
+
+type EntryStatus string
+
+const (
+	EntryDone       EntryStatus = "done"
+	EntryInProgress EntryStatus = "in_progress"
+	EntryBlocked    EntryStatus = "blocked"
+)
+
+// Günlük rapor içindeki tek bir iş kalemi.
+type ReportEntry struct {
+	Description string              `bson:"description"         json:"description"`
+	Hours       float64             `bson:"hours"               json:"hours"`
+	Category    string              `bson:"category,omitempty"  json:"category,omitempty"`
+	ProjectID   *primitive.ObjectID `bson:"projectId,omitempty" json:"projectId,omitempty"`
+	Project     string              `bson:"project,omitempty"   json:"project,omitempty"` // proje adı (denormalize) veya serbest metin
+	Status      EntryStatus         `bson:"status"              json:"status"`
+}
+
+type ReportStatus string
+
+const (
+	ReportDraft            ReportStatus = "draft"
+	ReportSubmitted        ReportStatus = "submitted"
+	ReportApproved         ReportStatus = "approved"
+	ReportChangesRequested ReportStatus = "changes_requested"
+)
+
+// Boş status (onay akışı öncesi kayıtlar) submitted sayılır.
+func (r Report) EffectiveStatus() ReportStatus {
+	if r.Status == "" {
+		return ReportSubmitted
+	}
+	return r.Status
+}
+
+type Attachment struct {
+	ID          primitive.ObjectID `bson:"_id"         json:"id"`
+	Key         string             `bson:"key"         json:"-"` // storage anahtarı
+	FileName    string             `bson:"fileName"    json:"fileName"`
+	ContentType string             `bson:"contentType" json:"contentType"`
+	Size        int64              `bson:"size"        json:"size"`
+	UploadedBy  primitive.ObjectID `bson:"uploadedBy"  json:"uploadedBy"`
+	UploadedAt  time.Time          `bson:"uploadedAt"  json:"uploadedAt"`
 }
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RevisionAction string
+
+const (
+	RevisionCreate   RevisionAction = "create"
+	RevisionUpdate   RevisionAction = "update"
+	RevisionRestore  RevisionAction = "restore"
+	RevisionBaseline RevisionAction = "baseline" // revizyon takibinden önceki son hal
+	RevisionImport   RevisionAction = "import"   // CSV import üzerine yazdı (geri alımı: restore)
+)
+
+// Raporun değişmez (immutable) bir revizyonu: değişiklikten sonraki değerler
+// ve bir öncekiler. Sadece eklenir, asla güncellenmez.
+type ReportRevision struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ReportID primitive.ObjectID `bson:"reportId"      json:"reportId"`
+	UserID   primitive.ObjectID `bson:"userId"        json:"userId"` // rapor sahibi
+	Date     string             `bson:"date"          json:"date"`
+	Rev      int                `bson:"rev"           json:"rev"`
+	Action   RevisionAction     `bson:"action"        json:"action"`
+
+	Content string        `bson:"content"           json:"content"`
+	Hours   float64       `bson:"hours"             json:"hours"`
+	Entries []ReportEntry `bson:"entries,omitempty" json:"entries,omitempty"`
+
+	PrevContent  *string       `bson:"prevContent,omitempty"  json:"prevContent,omitempty"`
+	PrevHours    *float64      `bson:"prevHours,omitempty"    json:"prevHours,omitempty"`
+	PrevEntries  []ReportEntry `bson:"prevEntries,omitempty"  json:"prevEntries,omitempty"`
+	RestoredFrom int           `bson:"restoredFrom,omitempty" json:"restoredFrom,omitempty"`
+
+	EditedBy     primitive.ObjectID `bson:"editedBy"               json:"editedBy"`
+	EditedByName string             `bson:"editedByName,omitempty" json:"editedByName,omitempty"`
+	EditedAt     time.Time          `bson:"editedAt"               json:"editedAt"`
+}
+
+// Revizyon takibi öncesi yazılmış raporun mevcut hali (rev=1).
+func (r Report) BaselineRevision() ReportRevision {
+	editedAt := r.CreatedAt
+	if r.UpdatedAt != nil {
+		editedAt = *r.UpdatedAt
+	}
+	editedBy := r.UserID
+	if r.UpdatedBy != nil {
+		editedBy = *r.UpdatedBy
+	}
+	return ReportRevision{
+		ReportID: r.ID,
+		UserID:   r.UserID,
+		Date:     r.Date,
+		Rev:      1,
+		Action:   RevisionBaseline,
+		Content:  r.Content,
+		Hours:    r.Hours,
+		Entries:  r.Entries,
+		EditedBy: editedBy,
+		EditedAt: editedAt,
+	}
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type UnlockStatus string
+
+const (
+	UnlockPending  UnlockStatus = "pending"
+	UnlockApproved UnlockStatus = "approved"
+	UnlockRejected UnlockStatus = "rejected"
+)
+
+// Kilitli (grace penceresi dışındaki) bir gün için rapor yazma izni talebi.
+// Onaylanan talep ExpiresAt'e kadar o günün raporunu yazmaya izin verir.
+type ReportUnlock struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"          json:"id"`
+	UserID       primitive.ObjectID `bson:"userId"                 json:"userId"`
+	UserName     string             `bson:"userName,omitempty"     json:"userName,omitempty"`
+	DepartmentID primitive.ObjectID `bson:"departmentId,omitempty" json:"departmentId,omitempty"`
+	Date         string             `bson:"date"                   json:"date"` // YYYY-MM-DD
+	Reason       string             `bson:"reason,omitempty"       json:"reason,omitempty"`
+	Status       UnlockStatus       `bson:"status"                 json:"status"`
+	CreatedAt    time.Time          `bson:"createdAt"              json:"createdAt"`
+
+	DecidedBy *primitive.ObjectID `bson:"decidedBy,omitempty" json:"decidedBy,omitempty"`
+	DecidedAt *time.Time          `bson:"decidedAt,omitempty" json:"decidedAt,omitempty"`
+	ExpiresAt *time.Time          `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"`
+}
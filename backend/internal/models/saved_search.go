@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DigestFrequency string
+
+const (
+	DigestNone   DigestFrequency = ""       // sadece elle çalıştırılır
+	DigestDaily  DigestFrequency = "daily"  // her gün Hour'da
+	DigestWeekly DigestFrequency = "weekly" // her hafta Weekday + Hour'da
+)
+
+// Kullanıcının kaydettiği rapor araması. Params, /api/reports/search sorgu
+// parametreleridir (q, department, user, status ...).
+type SavedSearch struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	OwnerID primitive.ObjectID `bson:"ownerId"       json:"ownerId"`
+	Name    string             `bson:"name"          json:"name"`
+	Params  map[string]string  `bson:"params"        json:"params"`
+
+	// Özet (digest) zamanlaması; Frequency boşsa zamanlanmamış
+	Frequency DigestFrequency `bson:"frequency,omitempty" json:"frequency,omitempty"`
+	Hour      int             `bson:"hour"                json:"hour"`    // 0-23 (sunucu saati)
+	Weekday   int             `bson:"weekday"             json:"weekday"` // 0=Pazar ... 6 (weekly)
+	Email     bool            `bson:"email"               json:"email"`   // uygulama içi bildirime ek olarak e-posta
+
+	NextRunAt   *time.Time `bson:"nextRunAt,omitempty"   json:"nextRunAt,omitempty"`
+	LastRunAt   *time.Time `bson:"lastRunAt,omitempty"   json:"lastRunAt,omitempty"`
+	LastMatches int        `bson:"lastMatches"           json:"lastMatches"`
+	LastError   string     `bson:"lastError,omitempty"   json:"lastError,omitempty"`
+
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
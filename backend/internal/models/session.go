@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Oturum: her login bir session açar; refresh token her kullanımda döner (rotate).
+// Access token'lar "sid" claim'i ile bu kayda bağlıdır.
+type Session struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty"             json:"id"`
+	UserID          primitive.ObjectID `bson:"userId"                    json:"userId"`
+	RefreshHash     string             `bson:"refreshHash"               json:"-"`
+	PrevRefreshHash string             `bson:"prevRefreshHash,omitempty" json:"-"` // reuse tespiti için
+	UserAgent       string             `bson:"userAgent,omitempty"       json:"userAgent,omitempty"`
+	IP              string             `bson:"ip,omitempty"              json:"ip,omitempty"`
+	CreatedAt       time.Time          `bson:"createdAt"                 json:"createdAt"`
+	LastUsedAt      time.Time          `bson:"lastUsedAt"                json:"lastUsedAt"`
+	ExpiresAt       time.Time          `bson:"expiresAt"                 json:"expiresAt"`
+	RevokedAt       *time.Time         `bson:"revokedAt,omitempty"       json:"revokedAt,omitempty"`
+}
@@ -15,11 +15,18 @@ const (
 )
 
 type User struct {
-	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name         string             `bson:"name" json:"name"`
-	Email        string             `bson:"email" json:"email"`
-	PasswordHash string             `bson:"passwordHash,omitempty" json:"-"`
-	Role         Role               `bson:"role" json:"role"`
-	Department   string             `bson:"department,omitempty" json:"department,omitempty"`
-	CreatedAt    time.Time          `bson:"createdAt,omitempty" json:"createdAt,omitempty"`
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Name          string              `bson:"name" json:"name"`
+	Email         string              `bson:"email" json:"email"`
+	PasswordHash  string              `bson:"passwordHash,omitempty" json:"-"`
+	Role          Role                `bson:"role" json:"role"`
+	DepartmentID  primitive.ObjectID  `bson:"departmentId,omitempty" json:"departmentId,omitempty"`
+	Department    string              `bson:"department,omitempty" json:"department,omitempty"` // denormalize: departman adı (gösterim için)
+	CreatedAt     time.Time           `bson:"createdAt,omitempty" json:"createdAt,omitempty"`
+	TokenVersion  int                 `bson:"tokenVersion,omitempty" json:"-"` // "sign out everywhere" ile artar
+	DeactivatedAt *time.Time          `bson:"deactivatedAt,omitempty" json:"deactivatedAt,omitempty"`
+	ImportBatchID *primitive.ObjectID `bson:"importBatchId,omitempty" json:"importBatchId,omitempty"` // CSV import ile geldiyse
 }
+
+// Deaktif kullanıcılar giriş yapamaz, durum/analitik sayımlarına girmez.
+func (u User) Active() bool { return u.DeactivatedAt == nil }
@@ -0,0 +1,270 @@
+// Package recurrence: hatırlatmalar için RRULE (RFC 5545) benzeri basit tekrar kuralları.
+//
+// Desteklenen parçalar (";" ile ayrılır, büyük/küçük harf duyarsız):
+//
+//	FREQ=DAILY|WEEKLY|MONTHLY   (zorunlu)
+//	INTERVAL=n                  her n gün/hafta/ayda bir (varsayılan 1)
+//	BYDAY=MO,TU,...             WEEKLY için gün(ler) (varsayılan başlangıç günü)
+//	BYMONTHDAY=n                MONTHLY için ayın günü, -1 = son gün (varsayılan başlangıç günü)
+//	BYHOUR=h;BYMINUTE=m         saat (varsayılan başlangıç saati)
+//	UNTIL=YYYYMMDD[THHMMSS[Z]]  bu zamandan sonra tekrar yok (tarih ise gün sonu dahil)
+//	COUNT=n                     en fazla n tekrar
+//
+// Örnek: her cuma 16:00, yıl sonuna kadar
+//
+//	FREQ=WEEKLY;BYDAY=FR;BYHOUR=16;BYMINUTE=0;UNTIL=20261231
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Freq string
+
+const (
+	Daily   Freq = "DAILY"
+	Weekly  Freq = "WEEKLY"
+	Monthly Freq = "MONTHLY"
+)
+
+var ErrInvalid = errors.New("invalid recurrence rule")
+
+// Bir sonraki tekrarı ararken bakılacak en fazla dönem sayısı. En kötü durum
+// ayın 29'u gibi bir gün için 4 yıllık (48 aylık) artık yıl döngüsüdür.
+const maxPeriods = 49
+
+type Rule struct {
+	Freq       Freq
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay int // 0 = başlangıç günü
+	Hour       int // -1 = başlangıç saati
+	Minute     int // -1 = başlangıç dakikası
+	Until      *time.Time
+	Count      int // 0 = sınırsız
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalid, fmt.Sprintf(format, args...))
+}
+
+// Parse: "FREQ=WEEKLY;BYDAY=FR;..." (başında "RRULE:" olabilir). UNTIL saat
+// dilimi belirtilmemişse loc'a göre yorumlanır.
+func Parse(s string, loc *time.Location) (Rule, error) {
+	r := Rule{Interval: 1, Hour: -1, Minute: -1}
+	s = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "RRULE:")
+	if s == "" {
+		return r, invalid("empty rule")
+	}
+	for _, part := range strings.Split(s, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			return r, invalid("%q is not KEY=VALUE", part)
+		}
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		switch k {
+		case "FREQ":
+			switch f := Freq(v); f {
+			case Daily, Weekly, Monthly:
+				r.Freq = f
+			default:
+				return r, invalid("FREQ must be DAILY, WEEKLY or MONTHLY")
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 || n > 366 {
+				return r, invalid("INTERVAL must be 1-366")
+			}
+			r.Interval = n
+		case "BYDAY":
+			for _, d := range strings.Split(v, ",") {
+				wd, ok := weekdays[strings.TrimSpace(d)]
+				if !ok {
+					return r, invalid("unknown BYDAY %q", d)
+				}
+				r.ByDay = append(r.ByDay, wd)
+			}
+		case "BYMONTHDAY":
+			n, err := strconv.Atoi(v)
+			if err != nil || n == 0 || n < -1 || n > 31 {
+				return r, invalid("BYMONTHDAY must be 1-31 or -1")
+			}
+			r.ByMonthDay = n
+		case "BYHOUR":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 || n > 23 {
+				return r, invalid("BYHOUR must be 0-23")
+			}
+			r.Hour = n
+		case "BYMINUTE":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 || n > 59 {
+				return r, invalid("BYMINUTE must be 0-59")
+			}
+			r.Minute = n
+		case "UNTIL":
+			t, err := parseUntil(v, loc)
+			if err != nil {
+				return r, err
+			}
+			r.Until = &t
+		case "COUNT":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return r, invalid("COUNT must be a positive number")
+			}
+			r.Count = n
+		default:
+			return r, invalid("unsupported part %s", k)
+		}
+	}
+	if r.Freq == "" {
+		return r, invalid("FREQ is required")
+	}
+	if len(r.ByDay) > 0 && r.Freq != Weekly {
+		return r, invalid("BYDAY is only supported with FREQ=WEEKLY")
+	}
+	if r.ByMonthDay != 0 && r.Freq != Monthly {
+		return r, invalid("BYMONTHDAY is only supported with FREQ=MONTHLY")
+	}
+	return r, nil
+}
+
+func parseUntil(v string, loc *time.Location) (time.Time, error) {
+	switch {
+	case len(v) == 8:
+		d, err := time.ParseInLocation("20060102", v, loc)
+		if err != nil {
+			break
+		}
+		// sadece tarih: o günün tamamı dahil
+		return d.AddDate(0, 0, 1).Add(-time.Second), nil
+	case strings.HasSuffix(v, "Z"):
+		if t, err := time.Parse("20060102T150405Z", v); err == nil {
+			return t, nil
+		}
+	default:
+		if t, err := time.ParseInLocation("20060102T150405", v, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, invalid("UNTIL must be YYYYMMDD or YYYYMMDDTHHMMSS[Z]")
+}
+
+func dayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// İki gün arasındaki takvim günü farkı (DST'den etkilenmez).
+func daysBetween(a, b time.Time) int {
+	ua := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	ub := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(ub.Sub(ua).Hours() / 24)
+}
+
+// haftalar pazartesiden başlar
+func weekOffset(wd time.Weekday) int { return (int(wd) + 6) % 7 }
+
+func monday(t time.Time) time.Time { return t.AddDate(0, 0, -weekOffset(t.Weekday())) }
+
+// first'ten from'a kadar geçen tam dönem (INTERVAL gün/hafta/ay) sayısı.
+func (r Rule) period(first, from time.Time) int {
+	switch r.Freq {
+	case Weekly:
+		return daysBetween(monday(first), monday(from)) / 7 / r.Interval
+	case Monthly:
+		return ((from.Year()-first.Year())*12 + int(from.Month()) - int(first.Month())) / r.Interval
+	}
+	return daysBetween(first, from) / r.Interval
+}
+
+// k. dönemdeki tekrar günleri (artan sırada). first başlangıç günüdür.
+func (r Rule) periodDays(first time.Time, k int) []time.Time {
+	switch r.Freq {
+	case Weekly:
+		mon := monday(first).AddDate(0, 0, 7*k*r.Interval)
+		if len(r.ByDay) == 0 {
+			return []time.Time{mon.AddDate(0, 0, weekOffset(first.Weekday()))}
+		}
+		offsets := make([]int, 0, len(r.ByDay))
+		for _, wd := range r.ByDay {
+			offsets = append(offsets, weekOffset(wd))
+		}
+		slices.Sort(offsets)
+		days := make([]time.Time, 0, len(offsets))
+		for _, o := range slices.Compact(offsets) {
+			days = append(days, mon.AddDate(0, 0, o))
+		}
+		return days
+	case Monthly:
+		month := time.Date(first.Year(), first.Month()+time.Month(k*r.Interval), 1, 0, 0, 0, 0, first.Location())
+		last := month.AddDate(0, 1, -1).Day()
+		want := r.ByMonthDay
+		if want == 0 {
+			want = first.Day()
+		}
+		if want == -1 {
+			want = last
+		}
+		if want > last {
+			return nil // ayda o gün yoksa (örn. 31) o ay atlanır
+		}
+		return []time.Time{month.AddDate(0, 0, want-1)}
+	}
+	return []time.Time{first.AddDate(0, 0, k*r.Interval)}
+}
+
+// Next: start'tan (ilk tekrar adayı; saat/dakika varsayılanı) itibaren, after'dan
+// kesin sonra gelen ilk tekrar. Günler tek tek taranmaz, doğrudan after'ın düştüğü
+// dönemden başlanır; bu yüzden büyük INTERVAL'ler de sorunsuzdur. UNTIL'i geçtiyse
+// ya da maxPeriods dönem içinde bulunamazsa false. COUNT burada uygulanmaz;
+// tekrar sayısını çağıran tutar.
+func (r Rule) Next(start, after time.Time) (time.Time, bool) {
+	start = start.Truncate(time.Minute)
+	hour, minute := r.Hour, r.Minute
+	if hour < 0 {
+		hour = start.Hour()
+	}
+	if minute < 0 {
+		minute = start.Minute()
+	}
+	loc := start.Location()
+
+	first := dayStart(start)
+	from := first
+	if after.After(start) {
+		from = dayStart(after.In(loc))
+	}
+	k0 := r.period(first, from)
+	for k := k0; k < k0+maxPeriods; k++ {
+		for _, d := range r.periodDays(first, k) {
+			t := time.Date(d.Year(), d.Month(), d.Day(), hour, minute, 0, 0, loc)
+			if t.Before(start) || !t.After(after) {
+				continue
+			}
+			if r.Until != nil && t.After(*r.Until) {
+				return time.Time{}, false
+			}
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// First: start'taki ya da sonraki ilk tekrar.
+func (r Rule) First(start time.Time) (time.Time, bool) {
+	return r.Next(start, start.Truncate(time.Minute).Add(-time.Nanosecond))
+}
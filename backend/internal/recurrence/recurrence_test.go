@@ -0,0 +1,240 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata" // DST testi için sistemde zoneinfo olmayabilir
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"RRULE:",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;INTERVAL=367",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=MONTHLY;BYMONTHDAY=-2",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;BYHOUR=24",
+		"FREQ=DAILY;BYMINUTE=60",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;UNTIL=2026-12-31",
+		"FREQ=DAILY;WKST=MO",
+		"FREQ",
+	} {
+		if _, err := Parse(s, time.UTC); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q) err = %v, want ErrInvalid", s, err)
+		}
+	}
+}
+
+func TestParseUntil(t *testing.T) {
+	ist := mustLoad(t, "Europe/Istanbul")
+	tests := []struct {
+		rule string
+		want time.Time
+	}{
+		// sadece tarih: o günün sonuna kadar dahil
+		{"FREQ=DAILY;UNTIL=20261231", time.Date(2026, 12, 31, 23, 59, 59, 0, ist)},
+		{"FREQ=DAILY;UNTIL=20261231T090000", time.Date(2026, 12, 31, 9, 0, 0, 0, ist)},
+		{"FREQ=DAILY;UNTIL=20261231T090000Z", time.Date(2026, 12, 31, 9, 0, 0, 0, time.UTC)},
+		{"rrule:freq=daily;until=20261231", time.Date(2026, 12, 31, 23, 59, 59, 0, ist)},
+	}
+	for _, tt := range tests {
+		r, err := Parse(tt.rule, ist)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.rule, err)
+		}
+		if r.Until == nil || !r.Until.Equal(tt.want) {
+			t.Errorf("Parse(%q).Until = %v, want %v", tt.rule, r.Until, tt.want)
+		}
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	ist := mustLoad(t, "Europe/Istanbul")
+	ny := mustLoad(t, "America/New_York")
+	at := func(loc *time.Location, y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		loc   *time.Location
+		start time.Time
+		want  []time.Time // ilk len(want) tekrar; sonrasında tekrar kalmamalıysa done
+		done  bool
+	}{
+		{
+			name:  "daily keeps start time",
+			rule:  "FREQ=DAILY",
+			loc:   ist,
+			start: at(ist, 2026, 3, 30, 9, 15),
+			want:  []time.Time{at(ist, 2026, 3, 30, 9, 15), at(ist, 2026, 3, 31, 9, 15), at(ist, 2026, 4, 1, 9, 15)},
+		},
+		{
+			name:  "daily interval with BYHOUR before start skips first day",
+			rule:  "FREQ=DAILY;INTERVAL=3;BYHOUR=8;BYMINUTE=0",
+			loc:   ist,
+			start: at(ist, 2026, 1, 1, 10, 0),
+			want:  []time.Time{at(ist, 2026, 1, 4, 8, 0), at(ist, 2026, 1, 7, 8, 0), at(ist, 2026, 1, 10, 8, 0)},
+		},
+		{
+			name:  "daily across spring-forward DST stays at wall clock",
+			rule:  "FREQ=DAILY;INTERVAL=2",
+			loc:   ny,
+			start: at(ny, 2026, 3, 6, 9, 0),
+			want:  []time.Time{at(ny, 2026, 3, 6, 9, 0), at(ny, 2026, 3, 8, 9, 0), at(ny, 2026, 3, 10, 9, 0)},
+		},
+		{
+			name:  "daily across fall-back DST stays at wall clock",
+			rule:  "FREQ=DAILY",
+			loc:   ny,
+			start: at(ny, 2026, 10, 31, 23, 30),
+			want:  []time.Time{at(ny, 2026, 10, 31, 23, 30), at(ny, 2026, 11, 1, 23, 30), at(ny, 2026, 11, 2, 23, 30)},
+		},
+		{
+			name:  "weekly BYDAY with date UNTIL includes the last day",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,FR;BYHOUR=16;BYMINUTE=0;UNTIL=20260116",
+			loc:   ist,
+			start: at(ist, 2026, 1, 1, 12, 0), // perşembe
+			want: []time.Time{
+				at(ist, 2026, 1, 2, 16, 0), at(ist, 2026, 1, 5, 16, 0),
+				at(ist, 2026, 1, 9, 16, 0), at(ist, 2026, 1, 12, 16, 0), at(ist, 2026, 1, 16, 16, 0),
+			},
+			done: true,
+		},
+		{
+			name:  "weekly BYDAY with datetime UNTIL excludes later occurrences",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,FR;BYHOUR=16;BYMINUTE=0;UNTIL=20260116T120000",
+			loc:   ist,
+			start: at(ist, 2026, 1, 1, 12, 0),
+			want: []time.Time{
+				at(ist, 2026, 1, 2, 16, 0), at(ist, 2026, 1, 5, 16, 0),
+				at(ist, 2026, 1, 9, 16, 0), at(ist, 2026, 1, 12, 16, 0),
+			},
+			done: true,
+		},
+		{
+			name:  "biweekly weeks start on monday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU,MO",
+			loc:   ist,
+			start: at(ist, 2026, 1, 4, 10, 0), // pazar: haftanın son günü
+			want: []time.Time{
+				at(ist, 2026, 1, 4, 10, 0), at(ist, 2026, 1, 12, 10, 0),
+				at(ist, 2026, 1, 18, 10, 0), at(ist, 2026, 1, 26, 10, 0),
+			},
+		},
+		{
+			name:  "monthly last day",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1;BYHOUR=18;BYMINUTE=0",
+			loc:   ist,
+			start: at(ist, 2027, 12, 15, 9, 0),
+			want: []time.Time{
+				at(ist, 2027, 12, 31, 18, 0), at(ist, 2028, 1, 31, 18, 0),
+				at(ist, 2028, 2, 29, 18, 0), at(ist, 2028, 3, 31, 18, 0), at(ist, 2028, 4, 30, 18, 0),
+			},
+		},
+		{
+			name:  "monthly 31 skips short months",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31",
+			loc:   ist,
+			start: at(ist, 2026, 1, 31, 9, 0),
+			want:  []time.Time{at(ist, 2026, 1, 31, 9, 0), at(ist, 2026, 3, 31, 9, 0), at(ist, 2026, 5, 31, 9, 0), at(ist, 2026, 7, 31, 9, 0), at(ist, 2026, 8, 31, 9, 0)},
+		},
+		{
+			name:  "monthly default day from start",
+			rule:  "FREQ=MONTHLY;INTERVAL=2",
+			loc:   ist,
+			start: at(ist, 2026, 11, 10, 9, 0),
+			want:  []time.Time{at(ist, 2026, 11, 10, 9, 0), at(ist, 2027, 1, 10, 9, 0), at(ist, 2027, 3, 10, 9, 0)},
+		},
+		{
+			name:  "monthly 29 with yearly interval waits for leap years",
+			rule:  "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=29",
+			loc:   ist,
+			start: at(ist, 2028, 2, 29, 9, 0),
+			want:  []time.Time{at(ist, 2028, 2, 29, 9, 0), at(ist, 2032, 2, 29, 9, 0), at(ist, 2036, 2, 29, 9, 0)},
+		},
+		{
+			name:  "large daily interval",
+			rule:  "FREQ=DAILY;INTERVAL=366",
+			loc:   ist,
+			start: at(ist, 2026, 1, 1, 9, 0),
+			want:  []time.Time{at(ist, 2026, 1, 1, 9, 0), at(ist, 2027, 1, 2, 9, 0), at(ist, 2028, 1, 3, 9, 0), at(ist, 2029, 1, 3, 9, 0)},
+		},
+		{
+			name:  "large weekly interval",
+			rule:  "FREQ=WEEKLY;INTERVAL=200;BYDAY=TU",
+			loc:   ist,
+			start: at(ist, 2026, 1, 6, 9, 0),
+			want:  []time.Time{at(ist, 2026, 1, 6, 9, 0), at(ist, 2029, 11, 6, 9, 0), at(ist, 2033, 9, 6, 9, 0)},
+		},
+		{
+			name:  "large monthly interval",
+			rule:  "FREQ=MONTHLY;INTERVAL=100",
+			loc:   ist,
+			start: at(ist, 2026, 1, 15, 9, 0),
+			want:  []time.Time{at(ist, 2026, 1, 15, 9, 0), at(ist, 2034, 5, 15, 9, 0), at(ist, 2042, 9, 15, 9, 0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse(tt.rule, tt.loc)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.rule, err)
+			}
+			got, ok := r.First(tt.start)
+			for i, want := range tt.want {
+				if !ok {
+					t.Fatalf("occurrence %d: none, want %v", i, want)
+				}
+				if !got.Equal(want) {
+					t.Fatalf("occurrence %d = %v, want %v", i, got, want)
+				}
+				got, ok = r.Next(tt.start, got)
+			}
+			if tt.done && ok {
+				t.Errorf("unexpected occurrence after the last one: %v", got)
+			}
+		})
+	}
+}
+
+func TestDaysBetween(t *testing.T) {
+	ny := mustLoad(t, "America/New_York")
+	tests := []struct {
+		a, b time.Time
+		want int
+	}{
+		// 23 saatlik gün (yaz saatine geçiş)
+		{time.Date(2026, 3, 8, 0, 0, 0, 0, ny), time.Date(2026, 3, 9, 0, 0, 0, 0, ny), 1},
+		// 25 saatlik gün (kış saatine dönüş)
+		{time.Date(2026, 11, 1, 0, 0, 0, 0, ny), time.Date(2026, 11, 2, 0, 0, 0, 0, ny), 1},
+		{time.Date(2026, 3, 1, 23, 0, 0, 0, ny), time.Date(2026, 3, 15, 1, 0, 0, 0, ny), 14},
+		{time.Date(2026, 1, 1, 0, 0, 0, 0, ny), time.Date(2027, 1, 1, 0, 0, 0, 0, ny), 365},
+		{time.Date(2026, 1, 10, 0, 0, 0, 0, ny), time.Date(2026, 1, 3, 0, 0, 0, 0, ny), -7},
+	}
+	for _, tt := range tests {
+		if got := daysBetween(tt.a, tt.b); got != tt.want {
+			t.Errorf("daysBetween(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -16,10 +16,21 @@ func Register(r *gin.Engine) {
 		{
 			auth.POST("/register", handlers.Register)
 			auth.POST("/login", handlers.Login)
+			auth.POST("/refresh", handlers.Refresh)
+			auth.POST("/logout", handlers.LogoutByRefreshToken, middleware.JWT(), handlers.Logout)
+			auth.POST("/logout-all", middleware.JWT(), handlers.LogoutAll)
 		}
 
 		api.GET("/me", middleware.JWT(), handlers.Me)
 
+		// --- INVITES ---
+		inv := api.Group("/invites", middleware.JWT(), middleware.RequireRole("admin", "superadmin"))
+		{
+			inv.GET("", handlers.ListInvites)
+			inv.POST("", handlers.CreateInvite)
+			inv.DELETE("/:id", handlers.RevokeInvite)
+		}
+
 		// --- REPORTS ---
 		reports := api.Group("/reports", middleware.JWT())
 		{
@@ -29,12 +40,43 @@ func Register(r *gin.Engine) {
 
 			reports.GET("/today", middleware.RequireRole("admin", "superadmin"), handlers.GetReportsByDay)
 			reports.GET("/search", middleware.RequireRole("admin", "superadmin"), handlers.SearchReports)
+			reports.GET("/export", middleware.RequireRole("admin", "superadmin"), handlers.ExportReports)
 			reports.GET("/status", middleware.RequireRole("admin", "superadmin"), handlers.GetReportStatus)
+			reports.GET("/compliance", middleware.RequireRole("admin", "superadmin"), handlers.GetReportCompliance)
 
 			reports.GET("/department/series", handlers.GetDepartmentSeries)
 			reports.GET("/department/breakdown", handlers.GetDepartmentBreakdown)
 
 			reports.GET("/user/:id", middleware.RequireRole("admin", "superadmin"), handlers.GetUserReports)
+			reports.GET("/user/:id/export", middleware.RequireRole("admin", "superadmin"), handlers.ExportUserReports)
+			reports.PUT("/user/:id/:date", middleware.RequireRole("admin", "superadmin"), handlers.UpsertUserReport)
+
+			// onay akışı
+			reports.GET("/review-queue", middleware.RequireRole("admin", "superadmin"), handlers.GetReviewQueue)
+			reports.POST("/:id/approve", middleware.RequireRole("admin", "superadmin"), handlers.ApproveReport)
+			reports.POST("/:id/reject", middleware.RequireRole("admin", "superadmin"), handlers.RejectReport)
+
+			// yorumlar (sahibi, departman admini, superadmin)
+			reports.GET("/:id/comments", handlers.ListReportComments)
+			reports.POST("/:id/comments", handlers.CreateReportComment)
+			reports.PATCH("/:id/comments/:commentId", handlers.UpdateReportComment)
+			reports.DELETE("/:id/comments/:commentId", handlers.DeleteReportComment)
+
+			// ek dosyalar
+			reports.POST("/:id/attachments", handlers.UploadAttachment)
+			reports.GET("/:id/attachments/:attachmentId", handlers.DownloadAttachment)
+			reports.DELETE("/:id/attachments/:attachmentId", handlers.DeleteAttachment)
+
+			// revizyon geçmişi
+			reports.GET("/:id/revisions", handlers.ListReportRevisions)
+			reports.GET("/:id/revisions/diff", handlers.DiffReportRevisions)
+			reports.POST("/:id/revisions/:rev/restore", middleware.RequireRole("admin", "superadmin"), handlers.RestoreReportRevision)
+
+			// kilitli günler için rapor yazma izni
+			reports.GET("/unlocks", handlers.ListReportUnlocks)
+			reports.POST("/unlocks", handlers.RequestReportUnlock)
+			reports.POST("/unlocks/:id/approve", middleware.RequireRole("admin", "superadmin"), handlers.ApproveReportUnlock)
+			reports.POST("/unlocks/:id/reject", middleware.RequireRole("admin", "superadmin"), handlers.RejectReportUnlock)
 		}
 
 		// --- REMINDERS ---
@@ -44,23 +86,102 @@ func Register(r *gin.Engine) {
 			rem.GET("/sent", middleware.RequireRole("admin", "superadmin"), handlers.ListSentReminders)
 			rem.POST("", middleware.RequireRole("admin", "superadmin"), handlers.CreateReminder)
 			rem.DELETE("/:id", middleware.RequireRole("admin", "superadmin"), handlers.DeleteReminder)
+
+			// okundu / onay / kapatma (hedef kitle) ve gönderen için okunma detayı
+			rem.POST("/:id/read", handlers.MarkReminderRead)
+			rem.POST("/:id/acknowledge", handlers.AcknowledgeReminder)
+			rem.POST("/:id/dismiss", handlers.DismissReminder)
+			rem.GET("/:id/receipts", middleware.RequireRole("admin", "superadmin"), handlers.GetReminderReceipts)
+
+			// ileri tarihli / tekrarlı hatırlatmalar (POST "" ile publishAt/rrule verilerek oluşturulur)
+			rem.GET("/scheduled", middleware.RequireRole("admin", "superadmin"), handlers.ListScheduledReminders)
+			rem.DELETE("/scheduled/:id", middleware.RequireRole("admin", "superadmin"), handlers.CancelScheduledReminder)
+
+			// rapor yazmayanlara otomatik hatırlatma + admin eskalasyonu
+			rem.GET("/auto", middleware.RequireRole("admin", "superadmin"), handlers.ListAutoReminderSchedules)
+			rem.GET("/auto/runs", middleware.RequireRole("admin", "superadmin"), handlers.ListAutoReminderRuns)
+			rem.PUT("/auto/:department", middleware.RequireRole("admin", "superadmin"), handlers.PutAutoReminderSchedule)
+			rem.DELETE("/auto/:department", middleware.RequireRole("admin", "superadmin"), handlers.DeleteAutoReminderSchedule)
+		}
+
+		// --- SAVED SEARCHES (zamanlanmış özetler) ---
+		saved := api.Group("/saved-searches", middleware.JWT(), middleware.RequireRole("admin", "superadmin"))
+		{
+			saved.GET("", handlers.ListSavedSearches)
+			saved.POST("", handlers.CreateSavedSearch)
+			saved.PATCH("/:id", handlers.UpdateSavedSearch)
+			saved.DELETE("/:id", handlers.DeleteSavedSearch)
+			saved.POST("/:id/run", handlers.RunSavedSearch)
+		}
+
+		// --- NOTIFICATIONS (kişisel) ---
+		notif := api.Group("/notifications", middleware.JWT())
+		{
+			notif.GET("", handlers.ListNotifications)
+			notif.POST("/read-all", handlers.MarkAllNotificationsRead)
+			notif.POST("/:id/read", handlers.MarkNotificationRead)
+		}
+
+		// --- USERS ---
+		users := api.Group("/users", middleware.JWT(), middleware.RequireRole("admin", "superadmin"))
+		{
+			users.GET("", handlers.GetUsersByDepartment)
+			users.GET("/:id", handlers.GetUser)
+			users.PATCH("/:id", handlers.UpdateUser)
+			users.POST("/:id/deactivate", handlers.DeactivateUser)
+			users.POST("/:id/reactivate", handlers.ReactivateUser)
+			users.DELETE("/:id", handlers.DeleteUser)
 		}
 
 		// --- DEPARTMENTS ---
-		api.GET(
-			"/departments",
-			middleware.JWT(),
-			middleware.RequireRole("admin", "superadmin"),
-			handlers.GetDepartments,
-		)
+		deps := api.Group("/departments", middleware.JWT())
+		{
+			deps.GET("", middleware.RequireRole("admin", "superadmin"), handlers.GetDepartments)
+			deps.POST("", middleware.RequireRole("superadmin"), handlers.CreateDepartment)
+			deps.PATCH("/:id", middleware.RequireRole("superadmin"), handlers.RenameDepartment)
+			deps.POST("/:id/archive", middleware.RequireRole("superadmin"), handlers.ArchiveDepartment)
+			deps.POST("/:id/restore", middleware.RequireRole("superadmin"), handlers.RestoreDepartment)
+			deps.DELETE("/:id", middleware.RequireRole("superadmin"), handlers.DeleteDepartment)
+			deps.PUT("/:id/workweek", middleware.RequireRole("admin", "superadmin"), handlers.SetDepartmentWorkweek)
+		}
+
+		// --- CALENDAR (tatiller, çalışma günleri, izinler) ---
+		cal := api.Group("/calendar", middleware.JWT())
+		{
+			cal.GET("", handlers.GetWorkCalendar)
+			cal.GET("/holidays", handlers.ListHolidays)
+			cal.POST("/holidays", middleware.RequireRole("superadmin"), handlers.CreateHoliday)
+			cal.DELETE("/holidays/:id", middleware.RequireRole("superadmin"), handlers.DeleteHoliday)
+			cal.GET("/leaves", handlers.ListLeaves)
+			cal.POST("/leaves", handlers.CreateLeave) // kendisi; admin departmanı; superadmin herkes
+			cal.DELETE("/leaves/:id", handlers.DeleteLeave)
+		}
+
+		// --- IMPORTS (CSV, geri alınabilir) ---
+		imports := api.Group("/imports", middleware.JWT(), middleware.RequireRole("superadmin"))
+		{
+			imports.GET("", handlers.ListImports)
+			imports.POST("/users", handlers.ImportUsers)
+			imports.POST("/reports", handlers.ImportReports)
+			imports.POST("/:id/rollback", handlers.RollbackImport)
+		}
+
+		// --- PROJECTS ---
+		projects := api.Group("/projects", middleware.JWT())
+		{
+			projects.GET("", handlers.ListProjects) // herkes (aktif projeler)
+			projects.POST("", middleware.RequireRole("admin", "superadmin"), handlers.CreateProject)
+			projects.PATCH("/:id", middleware.RequireRole("admin", "superadmin"), handlers.UpdateProject)
+		}
 
 		// --- ANALYTICS (Company Overview) ---
-		analytics := api.Group("/analytics",
-			middleware.JWT(),
-			middleware.RequireRole("superadmin"),
-		)
+		analytics := api.Group("/analytics", middleware.JWT())
 		{
-			analytics.GET("/company", handlers.CompanyAnalytics)
+			analytics.GET("/company", middleware.RequireRole("superadmin"), handlers.CompanyAnalytics)
+
+			// proje saatleri ve bütçe
+			analytics.GET("/projects", middleware.RequireRole("admin", "superadmin"), handlers.ProjectHoursAnalytics)
+			analytics.GET("/projects/:id/burndown", middleware.RequireRole("admin", "superadmin"), handlers.ProjectBurndown)
 		}
 	}
 }
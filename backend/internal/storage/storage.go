@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+// Dosya saklama arayüzü (ek dosyalar vb.). Anahtarlar "/" ile ayrılmış göreli yollardır.
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+var current Storage
+
+// Init: STORAGE_DRIVER=local (varsayılan) | s3
+//
+//	local: STORAGE_LOCAL_DIR (varsayılan ./uploads)
+//	s3:    S3_ENDPOINT, S3_REGION, S3_BUCKET, S3_ACCESS_KEY, S3_SECRET_KEY, S3_FORCE_PATH_STYLE
+func Init() error {
+	switch driver := strings.ToLower(strings.TrimSpace(os.Getenv("STORAGE_DRIVER"))); driver {
+	case "", "local":
+		dir := strings.TrimSpace(os.Getenv("STORAGE_LOCAL_DIR"))
+		if dir == "" {
+			dir = "./uploads"
+		}
+		l, err := NewLocal(dir)
+		if err != nil {
+			return err
+		}
+		current = l
+	case "s3":
+		s, err := NewS3FromEnv()
+		if err != nil {
+			return err
+		}
+		current = s
+	default:
+		return fmt.Errorf("unknown STORAGE_DRIVER %q", driver)
+	}
+	return nil
+}
+
+// Aktif storage; Init çağrılmadıysa nil.
+func Current() Storage {
+	return current
+}
@@ -82,13 +82,100 @@ import (
 	"github.com/joho/godotenv"
 
 	"report-management-system/internal/db"
+	"report-management-system/internal/handlers"
+	"report-management-system/internal/migrations"
 	"report-management-system/internal/routes"
+	"report-management-system/internal/storage"
 )
 
 func main() {
 	// Load .env if present (no-op in prod)
 	_ = godotenv.Load()
 
+	// --- MongoDB ---
+	if err := db.Connect(os.Getenv("MONGO_URI")); err != nil {
+		log.Fatal(err)
+	}
+	defer db.Disconnect()
+
+	ctx := context.Background()
+
+	// CLI: `go run . migrate [status|refs]` etc. — run and exit
+	if len(os.Args) > 1 {
+		if err := runCLI(ctx, os.Args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Departments: index + seed if empty (migrations map users to these)
+	if err := db.InitDepartments(ctx); err != nil {
+		log.Fatal(err)
+	}
+	// Schema migrations run before unique indexes are (re)built
+	if !strings.EqualFold(strings.TrimSpace(os.Getenv("MIGRATE_ON_START")), "false") {
+		if _, err := migrations.Run(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if err := db.EnsureIndexes(ctx); err != nil {
+		log.Fatal(err)
+	}
+	if err := db.EnsureReminderIndexes(ctx); err != nil {
+		log.Fatal(err)
+	}
+	if err := db.EnsureInviteIndexes(ctx); err != nil {
+		log.Fatal(err)
+	}
+	if err := db.EnsureSessionIndexes(ctx); err != nil {
+		log.Fatal(err)
+	}
+	if err := db.EnsureReportUnlockIndexes(ctx); err != nil {
+		log.Fatal(err)
+	}
+	if err := db.EnsureReportRevisionIndexes(ctx); err != nil {
+		log.Fatal(err)
+	}
+	if err := db.EnsureProjectIndexes(ctx); err != nil {
+		log.Fatal(err)
+	}
+	if err := db.EnsureCommentIndexes(ctx); err != nil {
+		log.Fatal(err)
+	}
+	if err := db.EnsureSavedSearchIndexes(ctx); err != nil {
+		log.Fatal(err)
+	}
+	if err := db.EnsureImportIndexes(ctx); err != nil {
+		log.Fatal(err)
+	}
+	if err := db.EnsureCalendarIndexes(ctx); err != nil {
+		log.Fatal(err)
+	}
+	if err := db.EnsureAutoReminderIndexes(ctx); err != nil {
+		log.Fatal(err)
+	}
+	if err := db.EnsureReminderSeriesIndexes(ctx); err != nil {
+		log.Fatal(err)
+	}
+	if err := db.EnsureReminderReceiptIndexes(ctx); err != nil {
+		log.Fatal(err)
+	}
+
+	// Ek dosya deposu (STORAGE_DRIVER=local|s3)
+	if err := storage.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Kayıtlı aramaların zamanlanmış özetleri (bildirim + SMTP)
+	go handlers.StartSearchDigests(ctx)
+
+	// Rapor yazmayanlara otomatik hatırlatma / admin eskalasyonu
+	go handlers.StartAutoReminders(ctx)
+
+	// İleri tarihli / tekrarlı hatırlatmaların yayını
+	go handlers.StartReminderSeries(ctx)
+
 	// --- CORS ---
 	clientURL := strings.TrimSpace(os.Getenv("CLIENT_URL"))
 	allowOrigins := []string{
@@ -109,25 +196,6 @@ func main() {
 		MaxAge:           12 * time.Hour,
 	}))
 
-	// --- MongoDB ---
-	if err := db.Connect(os.Getenv("MONGO_URI")); err != nil {
-		log.Fatal(err)
-	}
-	defer db.Disconnect()
-
-	ctx := context.Background()
-
-	if err := db.EnsureIndexes(ctx); err != nil {
-		log.Fatal(err)
-	}
-	if err := db.EnsureReminderIndexes(ctx); err != nil {
-		log.Fatal(err)
-	}
-	// Departments: index + seed if empty
-	if err := db.InitDepartments(ctx); err != nil {
-		log.Fatal(err)
-	}
-
 	// --- Routes ---
 	routes.Register(r)
 
@@ -1,4 +1,5 @@
 import { getUser, logout } from '../../utils/auth'
+import { API_URL } from '../../utils/api'
 import './TopBar.css'
 
 export default function TopBar() {
@@ -12,7 +13,7 @@ export default function TopBar() {
             {user ? `Hoş geldin, ${user.name} ` : ''}
         </span>
       </div>
-      <button className="logoutBtn" onClick={logout}>Logout</button>
+      <button className="logoutBtn" onClick={() => logout(API_URL)}>Logout</button>
     </div>
   )
 }
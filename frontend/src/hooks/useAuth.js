@@ -1,5 +1,6 @@
 import { createContext, useContext, useEffect, useState } from "react";
-import { apiAuth } from "../utils/api";
+import { API_URL, apiAuth } from "../utils/api";
+import { clearSession, revokeSession, setSession } from "../utils/auth";
 
 const AuthCtx = createContext(null);
 
@@ -21,19 +22,18 @@ export function AuthProvider({ children }) {
   useEffect(() => { fetchMe(); }, []);
 
   async function login(email, password) {
-    const { token } = await apiAuth.login({ email, password });
-    localStorage.setItem("token", token);
+    setSession(await apiAuth.login({ email, password }));
     await fetchMe();
   }
 
   async function register(payload) {
-    const { token } = await apiAuth.register(payload);
-    localStorage.setItem("token", token);
+    setSession(await apiAuth.register(payload));
     await fetchMe();
   }
 
   function logout() {
-    localStorage.removeItem("token");
+    revokeSession(API_URL);
+    clearSession();
     setUser(null);
   }
 
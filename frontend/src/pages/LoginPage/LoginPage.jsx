@@ -97,6 +97,7 @@ import { useNavigate } from "react-router-dom";
 import "./LoginPage.css";
 import logo from "../../assets/prLogo2_rb.png";
 import { useToast } from "../../components/Toast/ToastProvider";
+import { setSession } from "../../utils/auth";
 
 export default function LoginPage() {
   const [email, setEmail] = useState("");
@@ -140,7 +141,7 @@ export default function LoginPage() {
         return;
       }
 
-      localStorage.setItem("token", data.token);
+      setSession(data);
       localStorage.setItem("user", JSON.stringify(data.user));
       toast.success("Signed in");
 
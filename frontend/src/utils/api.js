@@ -1,9 +1,9 @@
-import { getToken, logout } from "./auth";
+import { getToken, logout, refreshSession } from "./auth";
 
 export const API_URL = import.meta.env.VITE_API_URL || "http://localhost:5000/api";
 
 /* -------------------- core fetch -------------------- */
-export async function apiFetch(path, opts = {}) {
+export async function apiFetch(path, opts = {}, retried = false) {
   const token = getToken();
   const headers = {
     "Content-Type": "application/json",
@@ -13,8 +13,12 @@ export async function apiFetch(path, opts = {}) {
 
   const res = await fetch(`${API_URL}${path}`, { ...opts, headers });
 
-  if (res.status === 401) {
-    logout();
+  if (res.status === 401 && !path.startsWith("/auth/")) {
+    // access token süresi dolduysa bir kez yenileyip tekrar dene
+    if (!retried && (await refreshSession(API_URL))) {
+      return apiFetch(path, opts, true);
+    }
+    logout(API_URL);
     throw new Error("Unauthorized");
   }
   if (res.status === 204) return null;
@@ -1,10 +1,61 @@
 export const getToken = () => localStorage.getItem('token');
+export const getRefreshToken = () => localStorage.getItem('refreshToken');
 export const getUser  = () => {
   try { return JSON.parse(localStorage.getItem('user') || 'null'); }
   catch { return null; }
 };
-export const logout = () => {
+
+// Login/register/refresh yanıtındaki token'ları saklar
+export const setSession = ({ token, refreshToken } = {}) => {
+  if (token) localStorage.setItem('token', token);
+  if (refreshToken) localStorage.setItem('refreshToken', refreshToken);
+};
+
+export const clearSession = () => {
   localStorage.removeItem('token');
+  localStorage.removeItem('refreshToken');
   localStorage.removeItem('user');
+};
+
+// Sunucudaki oturumu kapatır (best effort): refresh token iptal edilir, access
+// token'ın süresi dolmuş olsa da çalışır. keepalive: ardından sayfa değişse de
+// istek tamamlanır.
+export function revokeSession(apiUrl) {
+  const refreshToken = getRefreshToken();
+  if (!apiUrl || !refreshToken) return;
+  fetch(`${apiUrl}/auth/logout`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ refreshToken }),
+    keepalive: true,
+  }).catch(() => {});
+}
+
+export const logout = (apiUrl) => {
+  revokeSession(apiUrl);
+  clearSession();
   window.location.href = '/';
 };
+
+// Access token kısa ömürlü: 401'de refresh token ile yenilenir (token rotasyonu).
+// Aynı anda gelen 401'ler tek bir /auth/refresh isteğini paylaşır.
+let refreshing = null;
+export function refreshSession(apiUrl) {
+  const refreshToken = getRefreshToken();
+  if (!refreshToken) return Promise.resolve(false);
+  if (!refreshing) {
+    refreshing = fetch(`${apiUrl}/auth/refresh`, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ refreshToken }),
+    })
+      .then(async (res) => {
+        if (!res.ok) return false;
+        setSession(await res.json());
+        return true;
+      })
+      .catch(() => false)
+      .finally(() => { refreshing = null; });
+  }
+  return refreshing;
+}
@@ -1,4 +1,5 @@
 import axios from "axios";
+import { logout, refreshSession } from "./auth";
 
 const http = axios.create({
   baseURL: import.meta.env.VITE_API_BASE || "/api",
@@ -10,4 +11,15 @@ http.interceptors.request.use((config) => {
   return config;
 });
 
+// 401: refresh token ile yenileyip isteği bir kez tekrarla
+http.interceptors.response.use(undefined, async (error) => {
+  const config = error.config;
+  if (error.response?.status === 401 && config && !config._retried) {
+    config._retried = true;
+    if (await refreshSession(http.defaults.baseURL)) return http(config);
+    logout(http.defaults.baseURL);
+  }
+  return Promise.reject(error);
+});
+
 export default http;